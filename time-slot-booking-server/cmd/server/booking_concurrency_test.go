@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/services"
+
+	"github.com/google/uuid"
+)
+
+// TestConcurrentBookingsRespectCapacity fires parallel bookings at one time
+// slot and checks that exactly its capacity is booked.
+func TestConcurrentBookingsRespectCapacity(t *testing.T) {
+	database := openTestDB(t)
+	ctx := context.Background()
+
+	const capacity = 5
+	const requests = 300
+
+	resource, err := services.NewResourceService(database).Create(ctx, &models.CreateResourceRequest{
+		Name:     "Stress court " + uuid.NewString(),
+		Type:     "court",
+		Capacity: capacity,
+	})
+	if err != nil {
+		t.Fatalf("failed to create resource: %v", err)
+	}
+
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	slot, err := services.NewTimeSlotService(database).Create(ctx, resource.ID, start, start.Add(time.Hour), capacity, nil)
+	if err != nil {
+		t.Fatalf("failed to create time slot: %v", err)
+	}
+
+	// One user per request, so per-user booking limits do not interfere
+	tokens := make([]string, requests)
+	for i := range tokens {
+		_, tokens[i] = createTestUser(t, database, "customer")
+	}
+
+	server := httptest.NewServer(newRouter(database, nil))
+	defer server.Close()
+
+	body, _ := json.Marshal(models.CreateBookingRequest{ResourceID: resource.ID, TimeSlotID: slot.ID})
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		statuses = make(map[int]int)
+		ready    = make(chan struct{})
+	)
+
+	for _, token := range tokens {
+		wg.Add(1)
+		go func(token string) {
+			defer wg.Done()
+			<-ready
+
+			req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/bookings", bytes.NewReader(body))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", "application/json")
+
+			status := 0
+			if resp, err := http.DefaultClient.Do(req); err == nil {
+				status = resp.StatusCode
+				resp.Body.Close()
+			}
+
+			mu.Lock()
+			statuses[status]++
+			mu.Unlock()
+		}(token)
+	}
+
+	close(ready)
+	wg.Wait()
+
+	if statuses[http.StatusCreated] != capacity {
+		t.Errorf("got %d bookings for a slot of capacity %d (statuses %v)", statuses[http.StatusCreated], capacity, statuses)
+	}
+	if statuses[http.StatusBadRequest] != requests-capacity {
+		t.Errorf("got %d refusals, want %d (statuses %v)", statuses[http.StatusBadRequest], requests-capacity, statuses)
+	}
+
+	var stored models.TimeSlot
+	if err := database.NewSelect().Model(&stored).Where("id = ?", slot.ID).Scan(ctx); err != nil {
+		t.Fatalf("failed to reload time slot: %v", err)
+	}
+	if stored.BookedCount != capacity {
+		t.Errorf("booked_count = %d, want %d", stored.BookedCount, capacity)
+	}
+	if stored.IsAvailable {
+		t.Error("full time slot is still available")
+	}
+
+	active, err := database.NewSelect().
+		Model((*models.Booking)(nil)).
+		Where("time_slot_id = ?", slot.ID).
+		Where("status IN ('pending', 'confirmed')").
+		Count(ctx)
+
+	if err != nil {
+		t.Fatalf("failed to count bookings: %v", err)
+	}
+	if active != capacity {
+		t.Errorf("%d active bookings, want %d", active, capacity)
+	}
+}
+
+// TestTimeSlotCapacityConstraint checks that the database itself refuses to
+// book a time slot beyond its capacity.
+func TestTimeSlotCapacityConstraint(t *testing.T) {
+	database := openTestDB(t)
+	ctx := context.Background()
+
+	resource, err := services.NewResourceService(database).Create(ctx, &models.CreateResourceRequest{
+		Name:     "Constraint court " + uuid.NewString(),
+		Type:     "court",
+		Capacity: 2,
+	})
+	if err != nil {
+		t.Fatalf("failed to create resource: %v", err)
+	}
+
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	slot, err := services.NewTimeSlotService(database).Create(ctx, resource.ID, start, start.Add(time.Hour), 2, nil)
+	if err != nil {
+		t.Fatalf("failed to create time slot: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		set     string
+		wantErr bool
+	}{
+		{"fill to capacity", "booked_count = capacity", false},
+		{"one over capacity", "booked_count = capacity + 1", true},
+		{"negative count", "booked_count = -1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := database.NewUpdate().
+				Model((*models.TimeSlot)(nil)).
+				Set(tt.set).
+				Where("id = ?", slot.ID).
+				Exec(ctx)
+
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "time_slot_capacity") {
+					t.Fatalf("got %v, want a time_slot_capacity violation", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestReleasedSeatsKeepSlotDisabled checks that cancelling a booking does
+// not reopen a time slot an admin has disabled.
+func TestReleasedSeatsKeepSlotDisabled(t *testing.T) {
+	database := openTestDB(t)
+	ctx := context.Background()
+
+	resource, err := services.NewResourceService(database).Create(ctx, &models.CreateResourceRequest{
+		Name:     "Disabled court " + uuid.NewString(),
+		Type:     "court",
+		Capacity: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to create resource: %v", err)
+	}
+
+	timeSlotService := services.NewTimeSlotService(database)
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	slot, err := timeSlotService.Create(ctx, resource.ID, start, start.Add(time.Hour), 1, nil)
+	if err != nil {
+		t.Fatalf("failed to create time slot: %v", err)
+	}
+
+	userID, _ := createTestUser(t, database, "customer")
+	bookingService := services.NewBookingService(database, nil)
+
+	booking, err := bookingService.Create(ctx, userID, &models.CreateBookingRequest{ResourceID: resource.ID, TimeSlotID: slot.ID})
+	if err != nil {
+		t.Fatalf("failed to book: %v", err)
+	}
+
+	if err := timeSlotService.UpdateAvailability(ctx, slot.ID, false); err != nil {
+		t.Fatalf("failed to disable time slot: %v", err)
+	}
+
+	if _, err := bookingService.Cancel(ctx, booking.ID, userID, "customer", &models.CancelBookingRequest{}); err != nil {
+		t.Fatalf("failed to cancel: %v", err)
+	}
+
+	var stored models.TimeSlot
+	if err := database.NewSelect().Model(&stored).Where("id = ?", slot.ID).Scan(ctx); err != nil {
+		t.Fatalf("failed to reload time slot: %v", err)
+	}
+	if stored.BookedCount != 0 {
+		t.Errorf("booked_count = %d, want 0", stored.BookedCount)
+	}
+	if stored.IsAvailable {
+		t.Error("cancellation reopened a disabled time slot")
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/services"
+)
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	file := fs.String("file", "", "output file (defaults to stdout)")
+	fs.Parse(args)
+
+	database, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	catalog, err := services.NewResourceService(database).ExportCatalog(context.Background())
+	if err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(catalog)
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "", "input file (defaults to stdin)")
+	fs.Parse(args)
+
+	in := io.Reader(os.Stdin)
+	if *file != "" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var catalog models.Catalog
+	if err := json.NewDecoder(in).Decode(&catalog); err != nil {
+		return fmt.Errorf("invalid catalog JSON: %w", err)
+	}
+
+	database, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	resources, timeSlots, err := services.NewResourceService(database).ImportCatalog(context.Background(), &catalog)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d resource(s) and %d time slot(s)\n", resources, timeSlots)
+	return nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"time-slot-booking-server/internal/config"
+	"time-slot-booking-server/internal/services"
+)
+
+func runGenerateSlots(args []string) error {
+	fs := flag.NewFlagSet("generate-slots", flag.ExitOnError)
+	days := fs.Int("days", config.AppConfig.SlotGenerationDays, "number of days ahead to generate, starting today")
+	fs.Parse(args)
+
+	database, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	created, err := services.NewTimeSlotService(database).GenerateAll(context.Background(), *days)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %d time slot(s) for the next %d day(s)\n", created, *days)
+	return nil
+}
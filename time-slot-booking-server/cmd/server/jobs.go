@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+
+	"time-slot-booking-server/internal/config"
+	"time-slot-booking-server/internal/db"
+	"time-slot-booking-server/internal/jobs"
+	"time-slot-booking-server/internal/logger"
+	"time-slot-booking-server/internal/payments"
+	"time-slot-booking-server/internal/services"
+)
+
+// backgroundJobs lists the periodic tasks run alongside the HTTP server.
+func backgroundJobs(database *db.DB, provider payments.Provider) []jobs.Job {
+	timeSlotService := services.NewTimeSlotService(database)
+	waitlistService := services.NewWaitlistService(database, provider)
+	bookingService := services.NewBookingService(database, provider)
+	creditService := services.NewCreditService(database)
+
+	return []jobs.Job{
+		{
+			Name:     "generate-time-slots",
+			Interval: config.AppConfig.SlotGenerationInterval,
+			Run: func(ctx context.Context) error {
+				created, err := timeSlotService.GenerateAll(ctx, config.AppConfig.SlotGenerationDays)
+				if created > 0 {
+					logger.Info().
+						Int("created", created).
+						Msg("Generated time slots from operating hours")
+				}
+				return err
+			},
+		},
+		{
+			Name:     "expire-waitlist-offers",
+			Interval: config.AppConfig.WaitlistSweepInterval,
+			Run: func(ctx context.Context) error {
+				expired, err := waitlistService.ExpireOffers(ctx)
+				if expired > 0 {
+					logger.Info().
+						Int("expired", expired).
+						Msg("Expired waitlist offers")
+				}
+				return err
+			},
+		},
+		{
+			Name:     "release-expired-holds",
+			Interval: config.AppConfig.HoldSweepInterval,
+			Run: func(ctx context.Context) error {
+				released, err := bookingService.ReleaseExpiredHolds(ctx)
+				if released > 0 {
+					logger.Info().
+						Int("released", released).
+						Msg("Released expired booking holds")
+				}
+				return err
+			},
+		},
+		{
+			Name:     "open-pending-payments",
+			Interval: config.AppConfig.HoldSweepInterval,
+			Run: func(ctx context.Context) error {
+				opened, err := bookingService.OpenPendingPayments(ctx)
+				if opened > 0 {
+					logger.Info().
+						Int("opened", opened).
+						Msg("Created payment intents for outstanding payments")
+				}
+				return err
+			},
+		},
+		{
+			Name:     "issue-pending-refunds",
+			Interval: config.AppConfig.HoldSweepInterval,
+			Run: func(ctx context.Context) error {
+				issued, err := bookingService.IssuePendingRefunds(ctx)
+				if issued > 0 {
+					logger.Info().
+						Int("issued", issued).
+						Msg("Issued pending refunds")
+				}
+				return err
+			},
+		},
+		{
+			Name:     "expire-approval-requests",
+			Interval: config.AppConfig.HoldSweepInterval,
+			Run: func(ctx context.Context) error {
+				expired, err := bookingService.ExpirePendingApprovals(ctx)
+				if expired > 0 {
+					logger.Info().
+						Int("expired", expired).
+						Msg("Expired unreviewed booking requests")
+				}
+				return err
+			},
+		},
+		{
+			Name:     "mark-no-shows",
+			Interval: config.AppConfig.NoShowSweepInterval,
+			Run: func(ctx context.Context) error {
+				marked, err := bookingService.MarkNoShows(ctx, config.AppConfig.NoShowGracePeriod)
+				if marked > 0 {
+					logger.Info().
+						Int("marked", marked).
+						Msg("Marked unattended bookings as no-shows")
+				}
+				return err
+			},
+		},
+		{
+			Name:     "expire-credit-packs",
+			Interval: config.AppConfig.CreditSweepInterval,
+			Run: func(ctx context.Context) error {
+				expired, err := creditService.ExpirePacks(ctx)
+				if expired > 0 {
+					logger.Info().
+						Int("expired", expired).
+						Msg("Expired credit packs")
+				}
+				return err
+			},
+		},
+	}
+}
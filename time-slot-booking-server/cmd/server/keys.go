@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"time-slot-booking-server/internal/config"
+	"time-slot-booking-server/internal/services"
+)
+
+func runRotateKeys(args []string) error {
+	fs := flag.NewFlagSet("rotate-keys", flag.ExitOnError)
+	oldKey := fs.String("old-key", config.AppConfig.EncryptionKey, "current encryption key (defaults to ENCRYPTION_KEY)")
+	newKey := fs.String("new-key", "", "new encryption key (16, 24 or 32 bytes)")
+	fs.Parse(args)
+
+	if *newKey == "" {
+		return fmt.Errorf("--new-key is required")
+	}
+	if *newKey == *oldKey {
+		return fmt.Errorf("new key must differ from the old key")
+	}
+
+	database, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	rotated, err := services.NewUserService(database).
+		RotateEncryptionKey(context.Background(), []byte(*oldKey), []byte(*newKey))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Re-encrypted %d user(s). Set ENCRYPTION_KEY to the new key before restarting the server.\n", rotated)
+	return nil
+}
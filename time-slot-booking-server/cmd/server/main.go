@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	// Embed the IANA database so resource time zones resolve on hosts without one
+	_ "time/tzdata"
+
+	"time-slot-booking-server/internal/config"
+	"time-slot-booking-server/internal/db"
+	"time-slot-booking-server/internal/logger"
+)
+
+const usage = `Usage: time-slot-booking <command> [arguments]
+
+Commands:
+  serve                               Migrate the database and start the HTTP server (default)
+  migrate up                          Apply pending database migrations
+  migrate down [N]                    Roll back the last N migrations (default 1)
+  migrate status                      List applied and pending migrations
+  seed                                Create demo doctors, courts and facilities with time slots
+  generate-slots [--days N]           Materialize time slots from resource operating hours
+  user list                           List users with their roles
+  user promote <email> --role <role>  Change a user's role (admin, provider, customer)
+  user member <email> [--off]         Grant or revoke membership for member pricing
+  rotate-keys --new-key <key>         Re-encrypt user data with a new ENCRYPTION_KEY
+  export [--file <path>]              Write resources and time slots as JSON
+  import [--file <path>]              Load resources and time slots from JSON
+`
+
+// command is a CLI subcommand. It receives the arguments after its name.
+type command func(args []string) error
+
+var commands = map[string]command{
+	"serve":          runServe,
+	"migrate":        runMigrate,
+	"seed":           runSeed,
+	"generate-slots": runGenerateSlots,
+	"user":           runUser,
+	"rotate-keys":    runRotateKeys,
+	"export":         runExport,
+	"import":         runImport,
+}
+
+func main() {
+	config.Load()
+	logger.SetLevel(config.AppConfig.LogLevel)
+
+	name, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	if name == "help" {
+		fmt.Print(usage)
+		return
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", name, usage)
+		os.Exit(2)
+	}
+
+	if err := cmd(args); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		os.Exit(1)
+	}
+}
+
+// openDB connects to the configured database. Callers must close it.
+func openDB() (*db.DB, error) {
+	database, err := db.NewConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return database, nil
+}
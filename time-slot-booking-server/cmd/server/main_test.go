@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"time-slot-booking-server/internal/config"
+	"time-slot-booking-server/internal/db"
+	"time-slot-booking-server/internal/models"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// openTestDB connects to the database named by TEST_DATABASE_URL and brings
+// its schema up to date. Tests that need a database are skipped without
+// one; the database should be a scratch one, as tests leave their rows
+// behind.
+func openTestDB(t *testing.T) *db.DB {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	config.Load()
+	config.AppConfig.DatabaseURL = url
+
+	database, err := db.NewConnection()
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	// Stay well below the server's connection limit under load
+	database.SetMaxOpenConns(20)
+
+	if _, err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+
+	return database
+}
+
+// createTestUser inserts an app user with the given role and returns its ID
+// and a bearer token for it.
+func createTestUser(t *testing.T, database *db.DB, role string) (uuid.UUID, string) {
+	t.Helper()
+
+	id := uuid.New()
+	user := &models.AppUser{
+		ID:               id,
+		Email:            []byte(id.String() + "@example.com"),
+		Name:             []byte("Test user"),
+		Provider:         "test",
+		ProviderUserID:   []byte(id.String()),
+		ProviderUserHash: "test-" + id.String(),
+		Role:             role,
+	}
+
+	if _, err := database.NewInsert().Model(user).Exec(context.Background()); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   id.String(),
+		"email": id.String() + "@example.com",
+		"role":  role,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(config.AppConfig.JWTSecret))
+
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	return id, token
+}
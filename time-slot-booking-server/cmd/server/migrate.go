@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected one of: up, down [N], status")
+	}
+
+	database, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := database.Migrate(ctx)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("Database is up to date")
+		}
+		for _, m := range applied {
+			fmt.Printf("Applied %04d_%s\n", m.Version, m.Name)
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+
+		reverted, err := database.Rollback(ctx, steps)
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Println("No migrations to roll back")
+		}
+		for _, m := range reverted {
+			fmt.Printf("Rolled back %04d_%s\n", m.Version, m.Name)
+		}
+
+	case "status":
+		status, err := database.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+		for _, m := range status {
+			applied := "pending"
+			if m.AppliedAt != nil {
+				applied = m.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%-40s %s\n", m.Version, m.Name, applied)
+		}
+
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"net/http"
+
+	"time-slot-booking-server/internal/db"
+	"time-slot-booking-server/internal/handlers"
+	"time-slot-booking-server/internal/middleware"
+	"time-slot-booking-server/internal/payments"
+	"time-slot-booking-server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func newRouter(database *db.DB, provider payments.Provider) http.Handler {
+	resourceService := services.NewResourceService(database)
+	timeSlotService := services.NewTimeSlotService(database)
+	bookingService := services.NewBookingService(database, provider)
+	blackoutService := services.NewBlackoutService(database, provider)
+	waitlistService := services.NewWaitlistService(database, provider)
+	poolService := services.NewPoolService(database)
+	bookingRuleService := services.NewBookingRuleService(database)
+	pricingRuleService := services.NewPricingRuleService(database)
+	paymentService := services.NewPaymentService(database, provider)
+	creditService := services.NewCreditService(database)
+
+	healthHandler := handlers.NewHealthHandler()
+	authHandler := handlers.NewAuthHandler(database)
+	resourceHandler := handlers.NewResourceHandler(resourceService)
+	availabilityHandler := handlers.NewAvailabilityHandler(timeSlotService)
+	bookingHandler := handlers.NewBookingHandler(bookingService)
+	blackoutHandler := handlers.NewBlackoutHandler(blackoutService)
+	waitlistHandler := handlers.NewWaitlistHandler(waitlistService)
+	poolHandler := handlers.NewPoolHandler(poolService)
+	bookingRuleHandler := handlers.NewBookingRuleHandler(bookingRuleService)
+	pricingRuleHandler := handlers.NewPricingRuleHandler(pricingRuleService)
+	paymentHandler := handlers.NewPaymentHandler(paymentService)
+	creditHandler := handlers.NewCreditHandler(creditService)
+
+	r := chi.NewRouter()
+	r.Use(middleware.Recovery)
+	r.Use(middleware.Logger)
+	r.Use(middleware.CORS)
+
+	r.Get("/health", healthHandler.Health)
+	r.Get("/debug", healthHandler.Debug)
+
+	// OAuth2 login flow
+	r.Get("/login/{provider}", authHandler.Login)
+	r.Get("/v1/api/callback", authHandler.Callback)
+
+	// Payment provider notifications are authenticated by their signature
+	r.Post("/webhooks/payments", paymentHandler.Webhook)
+
+	r.Route("/api", func(r chi.Router) {
+		r.Use(middleware.Auth)
+
+		r.Route("/resources", func(r chi.Router) {
+			r.Get("/", resourceHandler.GetAll)
+			r.Get("/type/{type}", resourceHandler.GetByType)
+			r.Get("/{id}", resourceHandler.GetByID)
+			r.Get("/{id}/next-available", availabilityHandler.NextAvailable)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.AdminOnly)
+				r.Post("/", resourceHandler.Create)
+				r.Put("/{id}", resourceHandler.Update)
+				r.Delete("/{id}", resourceHandler.Delete)
+				r.Get("/{id}/providers", resourceHandler.ListProviders)
+				r.Post("/{id}/providers", resourceHandler.AddProvider)
+				r.Delete("/{id}/providers/{userId}", resourceHandler.RemoveProvider)
+				r.Get("/{id}/dependencies", resourceHandler.ListDependencies)
+				r.Post("/{id}/dependencies", resourceHandler.AddDependency)
+				r.Delete("/{id}/dependencies/{poolId}", resourceHandler.RemoveDependency)
+			})
+		})
+
+		r.Route("/pools", func(r chi.Router) {
+			r.Get("/", poolHandler.GetAll)
+			r.Get("/{id}", poolHandler.GetByID)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.AdminOnly)
+				r.Post("/", poolHandler.Create)
+				r.Put("/{id}", poolHandler.Update)
+				r.Delete("/{id}", poolHandler.Delete)
+				r.Post("/{id}/members", poolHandler.AddMember)
+				r.Delete("/{id}/members/{resourceId}", poolHandler.RemoveMember)
+			})
+		})
+
+		r.Route("/booking-rules", func(r chi.Router) {
+			r.Get("/", bookingRuleHandler.GetAll)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.AdminOnly)
+				r.Post("/", bookingRuleHandler.Create)
+				r.Put("/{id}", bookingRuleHandler.Update)
+				r.Delete("/{id}", bookingRuleHandler.Delete)
+			})
+		})
+
+		r.Route("/pricing-rules", func(r chi.Router) {
+			r.Get("/", pricingRuleHandler.GetAll)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.AdminOnly)
+				r.Post("/", pricingRuleHandler.Create)
+				r.Put("/{id}", pricingRuleHandler.Update)
+				r.Delete("/{id}", pricingRuleHandler.Delete)
+			})
+		})
+
+		r.Route("/credit-products", func(r chi.Router) {
+			r.Get("/", creditHandler.GetProducts)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.AdminOnly)
+				r.Post("/", creditHandler.CreateProduct)
+				r.Put("/{id}", creditHandler.UpdateProduct)
+				r.Delete("/{id}", creditHandler.DeleteProduct)
+			})
+		})
+
+		r.Route("/credits", func(r chi.Router) {
+			r.Get("/", creditHandler.GetBalance)
+			r.Get("/transactions", creditHandler.GetTransactions)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.AdminOnly)
+				r.Post("/purchases", creditHandler.RecordPurchase)
+			})
+		})
+
+		r.Route("/availability", func(r chi.Router) {
+			r.Get("/search", availabilityHandler.Search)
+			r.Get("/{id}", availabilityHandler.GetAvailability)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.AdminOnly)
+				r.Post("/{id}", availabilityHandler.CreateTimeSlot)
+				r.Post("/{id}/bulk", availabilityHandler.CreateTimeSlotsBulk)
+				r.Post("/{id}/generate", availabilityHandler.GenerateTimeSlots)
+				r.Put("/slot/{id}/availability", availabilityHandler.UpdateAvailability)
+				r.Delete("/slot/{id}", availabilityHandler.DeleteTimeSlot)
+			})
+		})
+
+		r.Route("/blackouts", func(r chi.Router) {
+			r.Use(middleware.AdminOnly)
+			r.Get("/", blackoutHandler.List)
+			r.Post("/", blackoutHandler.Create)
+			r.Delete("/{id}", blackoutHandler.Delete)
+		})
+
+		r.Route("/bookings", func(r chi.Router) {
+			r.Get("/", bookingHandler.GetUserBookings)
+			r.Post("/", bookingHandler.Create)
+			r.Post("/hold", bookingHandler.Hold)
+			r.Post("/quote", bookingHandler.Quote)
+			r.Post("/check-conflicts", bookingHandler.CheckConflicts)
+			r.Get("/approvals", bookingHandler.ListPendingApprovals)
+			r.Get("/no-shows", bookingHandler.GetNoShows)
+			r.Get("/series", bookingHandler.GetUserSeries)
+			r.Post("/series", bookingHandler.CreateSeries)
+			r.Get("/series/{id}", bookingHandler.GetSeries)
+			r.Post("/series/{id}/cancel", bookingHandler.CancelSeries)
+			r.Get("/bundles", bookingHandler.GetUserBundles)
+			r.Post("/bundles", bookingHandler.CreateBundle)
+			r.Get("/bundles/{id}", bookingHandler.GetBundle)
+			r.Post("/bundles/{id}/cancel", bookingHandler.CancelBundle)
+			r.Get("/{id}", bookingHandler.GetByID)
+			r.Get("/{id}/transitions", bookingHandler.GetTransitions)
+			r.Get("/{id}/payments", paymentHandler.GetForBooking)
+			r.Post("/{id}/payments/simulate", paymentHandler.Simulate)
+			r.Get("/{id}/refunds", bookingHandler.GetRefunds)
+			r.Post("/{id}/confirm", bookingHandler.ConfirmHold)
+			r.Post("/{id}/approve", bookingHandler.Approve)
+			r.Post("/{id}/reject", bookingHandler.Reject)
+			r.Post("/{id}/attendance", bookingHandler.MarkAttendance)
+			r.Get("/{id}/cancellation", bookingHandler.PreviewCancel)
+			r.Put("/{id}/cancel", bookingHandler.Cancel)
+			r.Post("/{id}/reschedule", bookingHandler.Reschedule)
+			r.Post("/{id}/party", bookingHandler.ReduceParty)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.AdminOnly)
+				r.Post("/{id}/refunds", bookingHandler.IssueRefund)
+			})
+		})
+
+		r.Route("/waitlist", func(r chi.Router) {
+			r.Get("/", waitlistHandler.List)
+			r.Post("/", waitlistHandler.Join)
+			r.Delete("/{id}", waitlistHandler.Leave)
+			r.Post("/{id}/claim", waitlistHandler.Claim)
+		})
+	})
+
+	// Everything else is the embedded single page app
+	r.Get("/*", handlers.StaticHandler())
+
+	return r
+}
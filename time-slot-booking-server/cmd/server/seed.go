@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/services"
+)
+
+// amount is a price in minor units of the resource's currency.
+func amount(minor int64) *models.Money { return &models.Money{Amount: minor} }
+
+// seedResources are the demo resources created by the seed command. Their
+// time slots are generated from the operating hours.
+var seedResources = []models.CreateResourceRequest{
+	{
+		Name:        "Dr. Asha Rao",
+		Type:        "doctor",
+		Description: "General physician",
+		Location:    "Clinic Block A",
+		Capacity:    1,
+		TimeZone:    "Asia/Kolkata",
+		OperatingHours: &models.OperatingHours{
+			Open:          "09:00",
+			Close:         "17:00",
+			Weekly:        map[string][]models.TimeInterval{"saturday": {{Open: "09:00", Close: "13:00"}}, "sunday": {}},
+			SlotMinutes:   30,
+			BufferMinutes: 10,
+			DefaultPrice:  amount(4000),
+		},
+	},
+	{
+		Name:             "Dr. Vikram Menon",
+		Type:             "doctor",
+		Description:      "Orthopaedic specialist",
+		Location:         "Clinic Block B",
+		Capacity:         1,
+		TimeZone:         "Asia/Kolkata",
+		RequiresApproval: true,
+		OperatingHours: &models.OperatingHours{
+			Weekly: map[string][]models.TimeInterval{
+				"monday":    {{Open: "14:00", Close: "18:00"}},
+				"wednesday": {{Open: "14:00", Close: "18:00"}},
+				"friday":    {{Open: "10:00", Close: "13:00"}, {Open: "14:00", Close: "18:00"}},
+			},
+			SlotMinutes:  45,
+			DefaultPrice: amount(7500),
+		},
+	},
+	{
+		Name:        "Badminton Court 1",
+		Type:        "court",
+		Description: "Indoor wooden badminton court",
+		Location:    "Marathalli",
+		Tags:        []string{"badminton", "indoor", "wooden"},
+		Capacity:    4,
+		TimeZone:    "Asia/Kolkata",
+		OperatingHours: &models.OperatingHours{
+			Open:         "06:00",
+			Close:        "22:00",
+			SlotMinutes:  60,
+			DefaultPrice: amount(2550),
+		},
+	},
+	{
+		Name:        "Badminton Court 2",
+		Type:        "court",
+		Description: "Indoor synthetic badminton court",
+		Location:    "Marathalli",
+		Tags:        []string{"badminton", "indoor", "synthetic"},
+		Capacity:    4,
+		TimeZone:    "Asia/Kolkata",
+		OperatingHours: &models.OperatingHours{
+			Open:         "06:00",
+			Close:        "22:00",
+			SlotMinutes:  60,
+			DefaultPrice: amount(2550),
+		},
+	},
+	{
+		Name:             "Main Hall",
+		Type:             "facility",
+		Description:      "Multi-purpose hall for events and classes",
+		Location:         "Community Centre",
+		Capacity:         50,
+		TimeZone:         "Asia/Kolkata",
+		RequiresApproval: true,
+		OperatingHours: &models.OperatingHours{
+			Open:          "08:00",
+			Close:         "22:00",
+			SlotMinutes:   120,
+			BufferMinutes: 30,
+			DefaultPrice:  amount(12000),
+		},
+	},
+}
+
+func runSeed(args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	days := fs.Int("days", 7, "number of days of time slots to generate, starting today")
+	fs.Parse(args)
+
+	database, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+	resourceService := services.NewResourceService(database)
+	timeSlotService := services.NewTimeSlotService(database)
+
+	existing, err := resourceService.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	seeded := make(map[string]bool, len(existing))
+	for _, r := range existing {
+		seeded[r.Name] = true
+	}
+
+	for _, seed := range seedResources {
+		if seeded[seed.Name] {
+			fmt.Printf("Skipping %s: already exists\n", seed.Name)
+			continue
+		}
+
+		req := seed
+		resource, err := resourceService.Create(ctx, &req)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", seed.Name, err)
+		}
+
+		slots, err := timeSlotService.Generate(ctx, resource.ID, *days)
+		if err != nil {
+			return fmt.Errorf("failed to create time slots for %s: %w", seed.Name, err)
+		}
+
+		fmt.Printf("Created %s (%s) with %d time slots\n", resource.Name, resource.Type, len(slots))
+	}
+
+	return nil
+}
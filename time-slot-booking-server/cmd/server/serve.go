@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"time-slot-booking-server/internal/config"
+	"time-slot-booking-server/internal/jobs"
+	"time-slot-booking-server/internal/logger"
+	"time-slot-booking-server/internal/payments"
+)
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	skipMigrate := fs.Bool("skip-migrate", false, "do not apply pending migrations on start")
+	fs.Parse(args)
+
+	database, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	// Bring the schema up to date before accepting traffic; the migration
+	// lock keeps concurrently starting replicas from racing each other
+	if !*skipMigrate {
+		if _, err := database.Migrate(context.Background()); err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+	}
+
+	provider, err := payments.New(config.AppConfig.PaymentProvider, config.AppConfig.PaymentWebhookSecret)
+	if err != nil {
+		return err
+	}
+
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	jobs.Start(jobCtx, backgroundJobs(database, provider)...)
+
+	srv := &http.Server{
+		Addr:    config.AppConfig.Port,
+		Handler: newRouter(database, provider),
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info().
+			Str("addr", srv.Addr).
+			Str("environment", config.AppConfig.Environment).
+			Str("payment_provider", config.AppConfig.PaymentProvider).
+			Msg("Server starting")
+
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-stop:
+	}
+
+	logger.Info().Msg("Server shutting down")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return srv.Shutdown(ctx)
+}
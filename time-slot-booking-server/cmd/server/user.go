@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"time-slot-booking-server/internal/services"
+)
+
+func runUser(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected one of: list, promote, member")
+	}
+
+	database, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	userService := services.NewUserService(database)
+	ctx := context.Background()
+
+	switch args[0] {
+	case "list":
+		users, err := userService.GetAll(ctx)
+		if err != nil {
+			return err
+		}
+		for _, u := range users {
+			member := ""
+			if u.IsMember {
+				member = "member"
+			}
+			fmt.Printf("%s  %-9s %-6s %-10s %s\n", u.ID, u.Role, member, u.Provider, u.Email)
+		}
+		return nil
+
+	case "promote":
+		fs := flag.NewFlagSet("user promote", flag.ExitOnError)
+		role := fs.String("role", "admin", "role to assign: admin, provider or customer")
+
+		// Accept the email before or after the flags
+		rest := args[1:]
+		var email string
+		if len(rest) > 0 && !strings.HasPrefix(rest[0], "-") {
+			email, rest = rest[0], rest[1:]
+		}
+		fs.Parse(rest)
+		if email == "" {
+			email = fs.Arg(0)
+		}
+		if email == "" {
+			return fmt.Errorf("usage: user promote <email> --role <role>")
+		}
+
+		user, err := userService.GetByEmail(ctx, email)
+		if err != nil {
+			return err
+		}
+
+		if err := userService.UpdateRole(ctx, user.ID, *role); err != nil {
+			return err
+		}
+
+		fmt.Printf("User %s (%s) is now %s\n", user.Email, user.ID, *role)
+		return nil
+
+	case "member":
+		fs := flag.NewFlagSet("user member", flag.ExitOnError)
+		off := fs.Bool("off", false, "revoke membership instead of granting it")
+
+		rest := args[1:]
+		var email string
+		if len(rest) > 0 && !strings.HasPrefix(rest[0], "-") {
+			email, rest = rest[0], rest[1:]
+		}
+		fs.Parse(rest)
+		if email == "" {
+			email = fs.Arg(0)
+		}
+		if email == "" {
+			return fmt.Errorf("usage: user member <email> [--off]")
+		}
+
+		user, err := userService.GetByEmail(ctx, email)
+		if err != nil {
+			return err
+		}
+
+		if err := userService.SetMember(ctx, user.ID, !*off); err != nil {
+			return err
+		}
+
+		if *off {
+			fmt.Printf("User %s (%s) is no longer a member\n", user.Email, user.ID)
+		} else {
+			fmt.Printf("User %s (%s) is now a member\n", user.Email, user.ID)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown user command %q", args[0])
+	}
+}
@@ -12,7 +12,12 @@ import (
 )
 
 func Encrypt(plaintext []byte) ([]byte, error) {
-	key := []byte(config.AppConfig.EncryptionKey)
+	return EncryptWithKey([]byte(config.AppConfig.EncryptionKey), plaintext)
+}
+
+// EncryptWithKey encrypts plaintext with AES-GCM using an explicit key, for
+// callers such as key rotation that cannot rely on the configured key.
+func EncryptWithKey(key, plaintext []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cipher (check ENCRYPTION_KEY length, must be 16, 24, or 32 bytes): %w", err)
@@ -33,7 +38,11 @@ func Encrypt(plaintext []byte) ([]byte, error) {
 }
 
 func Decrypt(ciphertext []byte) ([]byte, error) {
-	key := []byte(config.AppConfig.EncryptionKey)
+	return DecryptWithKey([]byte(config.AppConfig.EncryptionKey), ciphertext)
+}
+
+// DecryptWithKey decrypts ciphertext produced by EncryptWithKey.
+func DecryptWithKey(key, ciphertext []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -20,6 +22,21 @@ type Config struct {
 	AtlassianClientSecret   string
 	AppCallbackURL          string
 	RootAdmins              string
+	SlotGenerationDays      int
+	SlotGenerationInterval  time.Duration
+	WaitlistOfferTTL        time.Duration
+	WaitlistSweepInterval   time.Duration
+	BookingHoldTTL          time.Duration
+	HoldSweepInterval       time.Duration
+	NoShowGracePeriod       time.Duration
+	NoShowSweepInterval     time.Duration
+	NoShowLimit             int
+	NoShowWindow            time.Duration
+	DefaultCurrency         string
+	PaymentProvider         string
+	PaymentWebhookSecret    string
+	PaymentTimeout          time.Duration
+	CreditSweepInterval     time.Duration
 }
 
 var AppConfig *Config
@@ -43,6 +60,21 @@ func Load() {
 		AtlassianClientSecret: getEnv("ATLASSIAN_APP_SECRET", ""),
 		AppCallbackURL:        getEnv("APP_CALLBACK_URL", "http://localhost:8080/v1/api/callback"),
 		RootAdmins:            getEnv("ROOT_ADMINS", ""),
+		SlotGenerationDays:     getEnvInt("SLOT_GENERATION_DAYS", 56),
+		SlotGenerationInterval: getEnvDuration("SLOT_GENERATION_INTERVAL", time.Hour),
+		WaitlistOfferTTL:       getEnvDuration("WAITLIST_OFFER_TTL", 15*time.Minute),
+		WaitlistSweepInterval:  getEnvDuration("WAITLIST_SWEEP_INTERVAL", time.Minute),
+		BookingHoldTTL:         getEnvDuration("BOOKING_HOLD_TTL", 10*time.Minute),
+		HoldSweepInterval:      getEnvDuration("BOOKING_HOLD_SWEEP_INTERVAL", 30*time.Second),
+		NoShowGracePeriod:      getEnvDuration("NO_SHOW_GRACE_PERIOD", 15*time.Minute),
+		NoShowSweepInterval:    getEnvDuration("NO_SHOW_SWEEP_INTERVAL", 5*time.Minute),
+		NoShowLimit:            getEnvInt("NO_SHOW_LIMIT", 3),
+		NoShowWindow:           getEnvDuration("NO_SHOW_WINDOW", 30*24*time.Hour),
+		DefaultCurrency:        getEnv("DEFAULT_CURRENCY", "USD"),
+		PaymentProvider:        getEnv("PAYMENT_PROVIDER", "none"),
+		PaymentWebhookSecret:   getEnv("PAYMENT_WEBHOOK_SECRET", "your-payment-webhook-secret"),
+		PaymentTimeout:         getEnvDuration("PAYMENT_TIMEOUT", 15*time.Minute),
+		CreditSweepInterval:    getEnvDuration("CREDIT_EXPIRY_SWEEP_INTERVAL", time.Hour),
 	}
 }
 
@@ -52,3 +84,24 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+		log.Printf("Warning: invalid integer for %s, using default %d", key, defaultValue)
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+		log.Printf("Warning: invalid duration for %s, using default %s", key, defaultValue)
+	}
+	return defaultValue
+}
@@ -0,0 +1,295 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"time-slot-booking-server/migrations"
+
+	"github.com/uptrace/bun"
+)
+
+// migrationLockKey names the Postgres advisory lock that serializes migration
+// runs across server replicas.
+const migrationLockKey = "schema_migrations"
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is a single versioned schema change.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// SchemaMigration is a row of the migration history table.
+type SchemaMigration struct {
+	bun.BaseModel `bun:"schema_migrations"`
+	Version       int64     `json:"version" bun:"version,pk"`
+	Name          string    `json:"name" bun:"name,notnull"`
+	AppliedAt     time.Time `json:"applied_at" bun:"applied_at,notnull,default:now()"`
+}
+
+// MigrationStatus describes whether a known migration has been applied.
+type MigrationStatus struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+// LoadMigrations reads up/down migration pairs from fsys, ordered by version.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFileRe.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q: %w", entry.Name(), err)
+		}
+
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d has conflicting names %q and %q", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s must have both up and down files", m.Version, m.Name)
+		}
+		result = append(result, *m)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result, nil
+}
+
+// Migrate applies all pending migrations in version order and returns the
+// ones that were applied.
+func (db *DB) Migrate(ctx context.Context) ([]Migration, error) {
+	all, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	err = db.withMigrationLock(ctx, func(conn bun.Conn) error {
+		done, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range all {
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+
+			err := conn.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+				if _, err := tx.ExecContext(ctx, m.Up); err != nil {
+					return err
+				}
+
+				_, err := tx.NewInsert().
+					Model(&SchemaMigration{Version: m.Version, Name: m.Name}).
+					Exec(ctx)
+				return err
+			})
+
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s failed: %w", m.Version, m.Name, err)
+			}
+
+			applied = append(applied, m)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Rollback reverts the most recently applied migrations, newest first, and
+// returns the ones that were reverted.
+func (db *DB) Rollback(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("rollback steps must be greater than 0")
+	}
+
+	all, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[int64]Migration, len(all))
+	for _, m := range all {
+		known[m.Version] = m
+	}
+
+	var reverted []Migration
+	err = db.withMigrationLock(ctx, func(conn bun.Conn) error {
+		var history []SchemaMigration
+		err := conn.NewSelect().
+			Model(&history).
+			Order("version DESC").
+			Limit(steps).
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to read migration history: %w", err)
+		}
+
+		for _, row := range history {
+			m, ok := known[row.Version]
+			if !ok {
+				return fmt.Errorf("applied migration %04d_%s has no down file in this build", row.Version, row.Name)
+			}
+
+			err := conn.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+				if _, err := tx.ExecContext(ctx, m.Down); err != nil {
+					return err
+				}
+
+				_, err := tx.NewDelete().
+					Model((*SchemaMigration)(nil)).
+					Where("version = ?", m.Version).
+					Exec(ctx)
+				return err
+			})
+
+			if err != nil {
+				return fmt.Errorf("rollback of %04d_%s failed: %w", m.Version, m.Name, err)
+			}
+
+			reverted = append(reverted, m)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// MigrationStatus lists every known migration with the time it was applied,
+// if it has been.
+func (db *DB) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	all, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureMigrationTable(ctx, db.DB); err != nil {
+		return nil, err
+	}
+
+	var history []SchemaMigration
+	err = db.NewSelect().
+		Model(&history).
+		Order("version ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration history: %w", err)
+	}
+
+	appliedAt := make(map[int64]time.Time, len(history))
+	for _, row := range history {
+		appliedAt[row.Version] = row.AppliedAt
+	}
+
+	status := make([]MigrationStatus, 0, len(all))
+	for _, m := range all {
+		entry := MigrationStatus{Version: m.Version, Name: m.Name}
+		if at, ok := appliedAt[m.Version]; ok {
+			entry.AppliedAt = &at
+		}
+		status = append(status, entry)
+	}
+
+	return status, nil
+}
+
+// withMigrationLock runs fn on a dedicated connection holding a session-level
+// advisory lock, so only one process migrates the schema at a time.
+func (db *DB) withMigrationLock(ctx context.Context, fn func(conn bun.Conn) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext(?))", migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext(?))", migrationLockKey)
+
+	if err := ensureMigrationTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func ensureMigrationTable(ctx context.Context, db bun.IConn) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+func appliedMigrations(ctx context.Context, conn bun.Conn) (map[int64]struct{}, error) {
+	var versions []int64
+	err := conn.NewSelect().
+		Model((*SchemaMigration)(nil)).
+		Column("version").
+		Scan(ctx, &versions)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration history: %w", err)
+	}
+
+	done := make(map[int64]struct{}, len(versions))
+	for _, v := range versions {
+		done[v] = struct{}{}
+	}
+	return done, nil
+}
@@ -2,8 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+	"time-slot-booking-server/internal/config"
 	"time-slot-booking-server/internal/models"
 	"time-slot-booking-server/internal/services"
 
@@ -20,7 +24,7 @@ func NewAvailabilityHandler(timeSlotService *services.TimeSlotService) *Availabi
 }
 
 // @Summary Get availability for a resource
-// @Description Get available time slots for a resource within a date range
+// @Description Get available time slots for a resource on local dates (date, days, tz) or within an RFC3339 range (start_date, end_date), optionally only those with party_size free seats
 // @Tags availability
 // @Produce json
 // @Success 200 {object} models.AvailabilityResponse
@@ -33,34 +37,126 @@ func (h *AvailabilityHandler) GetAvailability(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Parse query parameters for date range
-	startDateStr := r.URL.Query().Get("start_date")
-	endDateStr := r.URL.Query().Get("end_date")
+	loc, err := h.timeSlotService.ResourceLocation(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Resource not found", http.StatusNotFound)
+		return
+	}
 
-	if startDateStr == "" || endDateStr == "" {
-		http.Error(w, "start_date and end_date query parameters are required", http.StatusBadRequest)
+	// An explicit tz overrides the resource's own time zone
+	if tz := r.URL.Query().Get("tz"); tz != "" {
+		loc, err = time.LoadLocation(tz)
+		if err != nil {
+			http.Error(w, "Invalid tz. Use an IANA time zone name", http.StatusBadRequest)
+			return
+		}
+	}
+
+	startDate, endDate, err := parseAvailabilityRange(r, loc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	startDate, err := time.Parse(time.RFC3339, startDateStr)
+	partySize := 1
+	if partyStr := r.URL.Query().Get("party_size"); partyStr != "" {
+		partySize, err = strconv.Atoi(partyStr)
+		if err != nil || partySize < 1 {
+			http.Error(w, "party_size must be a positive integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	timeSlots, err := h.timeSlotService.GetAvailable(r.Context(), id, startDate, endDate, partySize)
 	if err != nil {
-		http.Error(w, "Invalid start_date format. Use RFC3339", http.StatusBadRequest)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	endDate, err := time.Parse(time.RFC3339, endDateStr)
+	// Present times with the offset of the zone the caller asked about
+	for i := range timeSlots {
+		timeSlots[i].StartTime = timeSlots[i].StartTime.In(loc)
+		timeSlots[i].EndTime = timeSlots[i].EndTime.In(loc)
+	}
+
+	response := &models.AvailabilityResponse{
+		TimeZone:  loc.String(),
+		TimeSlots: timeSlots,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Get next available time slots
+// @Description Get the earliest bookable time slots of a resource starting after a given time (after, default now; limit, default 5) with party_size free seats (default 1), within the booking rules' notice and advance windows
+// @Tags availability
+// @Produce json
+// @Success 200 {object} models.AvailabilityResponse
+// @Router /api/resources/{id}/next-available [get]
+func (h *AvailabilityHandler) NextAvailable(w http.ResponseWriter, r *http.Request) {
+	resourceID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(resourceID)
 	if err != nil {
-		http.Error(w, "Invalid end_date format. Use RFC3339", http.StatusBadRequest)
+		http.Error(w, "Invalid resource ID", http.StatusBadRequest)
 		return
 	}
 
-	timeSlots, err := h.timeSlotService.GetAvailable(r.Context(), id, startDate, endDate)
+	loc, err := h.timeSlotService.ResourceLocation(r.Context(), id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Resource not found", http.StatusNotFound)
 		return
 	}
 
+	query := r.URL.Query()
+	if tz := query.Get("tz"); tz != "" {
+		loc, err = time.LoadLocation(tz)
+		if err != nil {
+			http.Error(w, "Invalid tz. Use an IANA time zone name", http.StatusBadRequest)
+			return
+		}
+	}
+
+	after := time.Now()
+	if afterStr := query.Get("after"); afterStr != "" {
+		after, err = time.Parse(time.RFC3339, afterStr)
+		if err != nil {
+			http.Error(w, "Invalid after format. Use RFC3339", http.StatusBadRequest)
+			return
+		}
+	}
+
+	limit := 5
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 50 {
+			http.Error(w, "limit must be between 1 and 50", http.StatusBadRequest)
+			return
+		}
+	}
+
+	partySize := 1
+	if partyStr := query.Get("party_size"); partyStr != "" {
+		partySize, err = strconv.Atoi(partyStr)
+		if err != nil || partySize < 1 {
+			http.Error(w, "party_size must be a positive integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	timeSlots, err := h.timeSlotService.NextAvailable(r.Context(), id, after, partySize, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for i := range timeSlots {
+		timeSlots[i].StartTime = timeSlots[i].StartTime.In(loc)
+		timeSlots[i].EndTime = timeSlots[i].EndTime.In(loc)
+	}
+
 	response := &models.AvailabilityResponse{
+		TimeZone:  loc.String(),
 		TimeSlots: timeSlots,
 	}
 
@@ -68,6 +164,148 @@ func (h *AvailabilityHandler) GetAvailability(w http.ResponseWriter, r *http.Req
 	json.NewEncoder(w).Encode(response)
 }
 
+// @Summary Search availability across resources
+// @Description Find free time slots on every resource matching type, tags, location, minimum duration and party size within a window (date, days, tz or start_date, end_date), earliest first
+// @Tags availability
+// @Produce json
+// @Success 200 {object} models.SlotSearchResponse
+// @Router /api/availability/search [get]
+func (h *AvailabilityHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	// Local dates are read in tz, or UTC when it is not given
+	loc := time.UTC
+	if tz := query.Get("tz"); tz != "" {
+		var err error
+		loc, err = time.LoadLocation(tz)
+		if err != nil {
+			http.Error(w, "Invalid tz. Use an IANA time zone name", http.StatusBadRequest)
+			return
+		}
+	}
+
+	from, to, err := parseAvailabilityRange(r, loc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	search := &models.SlotSearchQuery{
+		ResourceType: query.Get("type"),
+		Location:     query.Get("location"),
+		From:         from,
+		To:           to,
+		PartySize:    1,
+		Limit:        20,
+	}
+
+	if tags := query.Get("tags"); tags != "" {
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				search.Tags = append(search.Tags, tag)
+			}
+		}
+	}
+
+	intParams := []struct {
+		name     string
+		min, max int
+		dest     *int
+	}{
+		{"min_minutes", 1, 24 * 60, &search.MinMinutes},
+		{"party_size", 1, 1000, &search.PartySize},
+		{"limit", 1, 100, &search.Limit},
+		{"offset", 0, 100000, &search.Offset},
+	}
+	for _, p := range intParams {
+		value := query.Get(p.name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < p.min || n > p.max {
+			http.Error(w, fmt.Sprintf("%s must be between %d and %d", p.name, p.min, p.max), http.StatusBadRequest)
+			return
+		}
+		*p.dest = n
+	}
+
+	results, total, err := h.timeSlotService.Search(r.Context(), search)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Without an explicit tz, show each slot in its resource's own zone
+	for i := range results {
+		slotLoc := loc
+		if query.Get("tz") == "" {
+			if resourceLoc, err := time.LoadLocation(results[i].TimeZone); err == nil {
+				slotLoc = resourceLoc
+			}
+		}
+		results[i].StartTime = results[i].StartTime.In(slotLoc)
+		results[i].EndTime = results[i].EndTime.In(slotLoc)
+	}
+
+	response := &models.SlotSearchResponse{
+		Results: results,
+		Total:   total,
+		Limit:   search.Limit,
+		Offset:  search.Offset,
+	}
+	if next := search.Offset + len(results); next < total {
+		response.NextOffset = &next
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// parseAvailabilityRange reads the requested window either as a local date
+// (date=YYYY-MM-DD with optional days=N) in loc, or as RFC3339 start_date and
+// end_date instants.
+func parseAvailabilityRange(r *http.Request, loc *time.Location) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+
+	if dateStr := query.Get("date"); dateStr != "" {
+		day, err := time.ParseInLocation("2006-01-02", dateStr, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("Invalid date format. Use YYYY-MM-DD")
+		}
+
+		days := 1
+		if daysStr := query.Get("days"); daysStr != "" {
+			days, err = strconv.Atoi(daysStr)
+			if err != nil || days < 1 || days > 62 {
+				return time.Time{}, time.Time{}, fmt.Errorf("days must be between 1 and 62")
+			}
+		}
+
+		// AddDate keeps local midnight even when a day is 23 or 25 hours long
+		return day, day.AddDate(0, 0, days), nil
+	}
+
+	startDateStr := query.Get("start_date")
+	endDateStr := query.Get("end_date")
+
+	if startDateStr == "" || endDateStr == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("date or start_date and end_date query parameters are required")
+	}
+
+	startDate, err := time.Parse(time.RFC3339, startDateStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("Invalid start_date format. Use RFC3339")
+	}
+
+	endDate, err := time.Parse(time.RFC3339, endDateStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("Invalid end_date format. Use RFC3339")
+	}
+
+	return startDate, endDate, nil
+}
+
 // @Summary Create time slot
 // @Description Create a new time slot for a resource (admin only)
 // @Tags availability
@@ -84,10 +322,10 @@ func (h *AvailabilityHandler) CreateTimeSlot(w http.ResponseWriter, r *http.Requ
 	}
 
 	var req struct {
-		StartTime time.Time `json:"start_time"`
-		EndTime   time.Time `json:"end_time"`
-		Capacity  int       `json:"capacity"`
-		Price     *float64  `json:"price"`
+		StartTime time.Time     `json:"start_time"`
+		EndTime   time.Time     `json:"end_time"`
+		Capacity  int           `json:"capacity"`
+		Price     *models.Money `json:"price"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -122,12 +360,12 @@ func (h *AvailabilityHandler) CreateTimeSlotsBulk(w http.ResponseWriter, r *http
 	}
 
 	var req struct {
-		BaseStartTime time.Time `json:"base_start_time"`
-		Duration      int       `json:"duration"`  // Duration in minutes
-		Increment     int       `json:"increment"` // Increment between slots in minutes
-		Count         int       `json:"count"`     // Number of time slots to create
-		Capacity      int       `json:"capacity"`
-		Price         *float64  `json:"price"`
+		BaseStartTime time.Time     `json:"base_start_time"`
+		Duration      int           `json:"duration"`  // Duration in minutes
+		Increment     int           `json:"increment"` // Increment between slots in minutes
+		Count         int           `json:"count"`     // Number of time slots to create
+		Capacity      int           `json:"capacity"`
+		Price         *models.Money `json:"price"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -163,6 +401,51 @@ func (h *AvailabilityHandler) CreateTimeSlotsBulk(w http.ResponseWriter, r *http
 	json.NewEncoder(w).Encode(models.TimeSlotListResponse{TimeSlots: timeSlots})
 }
 
+// @Summary Generate time slots from schedule
+// @Description Materialize time slots from the resource's operating hours, skipping slots that already exist (admin only)
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.TimeSlotListResponse
+// @Router /api/availability/{id}/generate [post]
+func (h *AvailabilityHandler) GenerateTimeSlots(w http.ResponseWriter, r *http.Request) {
+	resourceID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(resourceID)
+	if err != nil {
+		http.Error(w, "Invalid resource ID", http.StatusBadRequest)
+		return
+	}
+
+	req := struct {
+		Days int `json:"days"` // Number of days ahead to generate, starting today
+	}{Days: config.AppConfig.SlotGenerationDays}
+
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.Days <= 0 || req.Days > 366 {
+		http.Error(w, "Days must be between 1 and 366", http.StatusBadRequest)
+		return
+	}
+
+	timeSlots, err := h.timeSlotService.Generate(r.Context(), id, req.Days)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if timeSlots == nil {
+		timeSlots = []models.TimeSlot{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.TimeSlotListResponse{TimeSlots: timeSlots})
+}
+
 // @Summary Update time slot availability
 // @Description Update the availability status of a time slot (admin only)
 // @Tags availability
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// New York springs forward on 2025-03-09 and falls back on 2025-11-02.
+func TestParseAvailabilityRangeAcrossDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	utc := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2025, month, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "spring forward day is 23 hours",
+			query:     "date=2025-03-09",
+			wantStart: utc(3, 9, 5),
+			wantEnd:   utc(3, 10, 4),
+		},
+		{
+			name:      "fall back day is 25 hours",
+			query:     "date=2025-11-02",
+			wantStart: utc(11, 2, 4),
+			wantEnd:   utc(11, 3, 5),
+		},
+		{
+			name:      "days span the spring forward",
+			query:     "date=2025-03-08&days=3",
+			wantStart: utc(3, 8, 5),
+			wantEnd:   utc(3, 11, 4),
+		},
+		{
+			name:      "days span the fall back",
+			query:     "date=2025-11-01&days=2",
+			wantStart: utc(11, 1, 4),
+			wantEnd:   utc(11, 3, 5),
+		},
+		{
+			name:      "explicit range is taken as is",
+			query:     "start_date=2025-03-09T01:00:00-05:00&end_date=2025-03-09T04:00:00-04:00",
+			wantStart: utc(3, 9, 6),
+			wantEnd:   utc(3, 9, 8),
+		},
+		{name: "days out of range", query: "date=2025-03-09&days=0", wantErr: true},
+		{name: "malformed date", query: "date=2025-3-9", wantErr: true},
+		{name: "missing range", query: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/?"+tt.query, nil)
+
+			start, end, err := parseAvailabilityRange(r, newYork)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %s - %s, want an error", start, end)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("got %s - %s, want %s - %s", start.UTC(), end.UTC(), tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+	"time-slot-booking-server/internal/middleware"
+	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type BlackoutHandler struct {
+	blackoutService *services.BlackoutService
+}
+
+func NewBlackoutHandler(blackoutService *services.BlackoutService) *BlackoutHandler {
+	return &BlackoutHandler{blackoutService: blackoutService}
+}
+
+// @Summary List blackout periods
+// @Description List blackout periods in a date range, optionally only those applying to a resource (admin only)
+// @Tags blackouts
+// @Produce json
+// @Success 200 {array} models.Blackout
+// @Router /api/blackouts [get]
+func (h *BlackoutHandler) List(w http.ResponseWriter, r *http.Request) {
+	from := time.Now()
+	to := from.AddDate(1, 0, 0)
+
+	if v := r.URL.Query().Get("from"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "Invalid from format. Use RFC3339", http.StatusBadRequest)
+			return
+		}
+		from = parsed
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "Invalid to format. Use RFC3339", http.StatusBadRequest)
+			return
+		}
+		to = parsed
+	}
+
+	var resourceID *uuid.UUID
+	if v := r.URL.Query().Get("resource_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, "Invalid resource ID", http.StatusBadRequest)
+			return
+		}
+		resourceID = &id
+	}
+
+	blackouts, err := h.blackoutService.List(r.Context(), resourceID, from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(blackouts)
+}
+
+// @Summary Create blackout period
+// @Description Close a time window globally, for a resource type or for a resource, optionally cancelling affected bookings (admin only)
+// @Tags blackouts
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.BlackoutResponse
+// @Router /api/blackouts [post]
+func (h *BlackoutHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateBlackoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	var createdBy uuid.UUID
+	if user := middleware.GetUser(r.Context()); user != nil {
+		createdBy, _ = uuid.Parse(user.ID)
+	}
+
+	response, err := h.blackoutService.Create(r.Context(), &req, createdBy)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Delete blackout period
+// @Description Delete a blackout period, reopening its time slots (admin only)
+// @Tags blackouts
+// @Success 204
+// @Router /api/blackouts/{id} [delete]
+func (h *BlackoutHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	blackoutID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(blackoutID)
+	if err != nil {
+		http.Error(w, "Invalid blackout ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.blackoutService.Delete(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 	"time-slot-booking-server/internal/middleware"
@@ -49,7 +51,7 @@ func (h *BookingHandler) GetUserBookings(w http.ResponseWriter, r *http.Request)
 }
 
 // @Summary Create new booking
-// @Description Create a new booking for a time slot
+// @Description Create a new booking for a time slot, for a free-form start_time to end_time range on resources in interval booking mode, or for start_time to end_time on any free member of a pool (pool_id). When payments are enabled, priced bookings stay pending with a payment to complete until it succeeds; pay_with_credits pays one credit per seat from the user's packs instead
 // @Tags bookings
 // @Accept json
 // @Produce json
@@ -73,7 +75,120 @@ func (h *BookingHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	booking, err := h.bookingService.Create(r.Context(), userID, req.ResourceID, req.TimeSlotID, req.Notes)
+	var booking *models.Booking
+	switch {
+	case req.PoolID != nil:
+		if req.StartTime == nil || req.EndTime == nil {
+			http.Error(w, "start_time and end_time are required when booking a pool", http.StatusBadRequest)
+			return
+		}
+		booking, err = h.bookingService.BookPool(r.Context(), userID, &req)
+	case req.TimeSlotID == uuid.Nil && req.StartTime != nil && req.EndTime != nil:
+		booking, err = h.bookingService.CreateInterval(r.Context(), userID, &req)
+	default:
+		booking, err = h.bookingService.Create(r.Context(), userID, &req)
+	}
+	if errors.Is(err, services.ErrNoShowLimit) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if writeRuleViolations(w, err) {
+		return
+	}
+	if errors.Is(err, services.ErrBookingConflict) || errors.Is(err, services.ErrPoolFull) || errors.Is(err, services.ErrPriceChanged) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, services.ErrInsufficientCredits) {
+		http.Error(w, err.Error(), http.StatusPaymentRequired)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(booking)
+}
+
+// @Summary Quote a booking
+// @Description Price a time slot, or a start_time/end_time range on an interval resource, for party_size under the current pricing rules, itemised line by line. Send the total back as quoted_total to book at that price
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.PriceQuote
+// @Router /api/bookings/quote [post]
+func (h *BookingHandler) Quote(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateBookingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	quote, err := h.bookingService.Quote(r.Context(), userID, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quote)
+}
+
+// @Summary Hold a time slot
+// @Description Reserve party_size seats as a pending booking during checkout; it is released if not confirmed before hold_expires_at
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Booking
+// @Router /api/bookings/hold [post]
+func (h *BookingHandler) Hold(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateBookingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	booking, err := h.bookingService.Hold(r.Context(), userID, &req)
+	if errors.Is(err, services.ErrNoShowLimit) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if writeRuleViolations(w, err) {
+		return
+	}
+	if errors.Is(err, services.ErrPriceChanged) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, services.ErrInsufficientCredits) {
+		http.Error(w, err.Error(), http.StatusPaymentRequired)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -84,8 +199,43 @@ func (h *BookingHandler) Create(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(booking)
 }
 
+// @Summary Confirm a hold
+// @Description Turn an unexpired hold into a confirmed booking
+// @Tags bookings
+// @Produce json
+// @Success 200 {object} models.Booking
+// @Router /api/bookings/{id}/confirm [post]
+func (h *BookingHandler) ConfirmHold(w http.ResponseWriter, r *http.Request) {
+	bookingID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(bookingID)
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	booking, err := h.bookingService.ConfirmHold(r.Context(), id, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(booking)
+}
+
 // @Summary Get booking by ID
-// @Description Retrieve a specific booking by its ID
+// @Description Retrieve a specific booking by its ID with its attendees. Users see their own bookings, providers those on their resources, admins any
 // @Tags bookings
 // @Produce json
 // @Success 200 {object} models.Booking
@@ -98,7 +248,18 @@ func (h *BookingHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	booking, err := h.bookingService.GetByID(r.Context(), id)
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	booking, err := h.bookingService.GetByID(r.Context(), id, userID, user.Role)
 	if err != nil {
 		http.Error(w, "Booking not found", http.StatusNotFound)
 		return
@@ -108,10 +269,52 @@ func (h *BookingHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(booking)
 }
 
+// @Summary Preview cancellation
+// @Description Show the fee and refund cancelling a booking now would have under its resource's cancellation policy; admins may preview a policy override (override=true)
+// @Tags bookings
+// @Produce json
+// @Success 200 {object} models.CancellationQuote
+// @Router /api/bookings/{id}/cancellation [get]
+func (h *BookingHandler) PreviewCancel(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	override := r.URL.Query().Get("override") == "true"
+
+	quote, err := h.bookingService.PreviewCancel(r.Context(), id, userID, user.Role, override)
+	if errors.Is(err, services.ErrCancelOverride) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quote)
+}
+
 // @Summary Cancel booking
-// @Description Cancel a booking
+// @Description Cancel a booking under its resource's cancellation policy. Bookings that have started cannot be cancelled; accept_fee rejects the cancellation if the fee exceeds it. Admins may cancel any booking and waive the policy (override). The refund due on a paid booking is issued through the payment provider
 // @Tags bookings
-// @Success 204
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Booking
 // @Router /api/bookings/{id}/cancel [put]
 func (h *BookingHandler) Cancel(w http.ResponseWriter, r *http.Request) {
 	bookingID := chi.URLParam(r, "id")
@@ -132,13 +335,117 @@ func (h *BookingHandler) Cancel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.bookingService.Cancel(r.Context(), id, userID)
+	// The body is optional; a bare PUT cancels under the policy
+	var req models.CancelBookingRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+	}
+
+	booking, err := h.bookingService.Cancel(r.Context(), id, userID, user.Role, &req)
+	if errors.Is(err, services.ErrCancelOverride) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, services.ErrFeeNotAccepted) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(booking)
+}
+
+// @Summary Reschedule booking
+// @Description Move a booking to another time slot, on the same resource or one of the same type, without giving up the original seat first
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Booking
+// @Router /api/bookings/{id}/reschedule [post]
+func (h *BookingHandler) Reschedule(w http.ResponseWriter, r *http.Request) {
+	bookingID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(bookingID)
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.RescheduleBookingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	booking, err := h.bookingService.Reschedule(r.Context(), id, userID, req.TimeSlotID)
+	if writeRuleViolations(w, err) {
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(booking)
+}
+
+// @Summary Reduce party size
+// @Description Shrink a booking's party without cancelling it; the freed seats go back to the time slot and its waitlist. Attendees, when given, replace the current list
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Booking
+// @Router /api/bookings/{id}/party [post]
+func (h *BookingHandler) ReduceParty(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.UpdatePartyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	booking, err := h.bookingService.ReduceParty(r.Context(), id, userID, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(booking)
 }
 
 // @Summary Check booking conflicts
@@ -161,6 +468,10 @@ func (h *BookingHandler) CheckConflicts(w http.ResponseWriter, r *http.Request)
 	}
 
 	err := h.bookingService.CheckConflicts(r.Context(), req.ResourceID, req.StartTime, req.EndTime)
+	if err != nil && !errors.Is(err, services.ErrBookingConflict) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	response := map[string]interface{}{
 		"has_conflicts": err != nil,
@@ -172,3 +483,314 @@ func (h *BookingHandler) CheckConflicts(w http.ResponseWriter, r *http.Request)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+// @Summary List pending approvals
+// @Description List booking requests awaiting approval on resources the current admin or provider may review
+// @Tags bookings
+// @Produce json
+// @Success 200 {array} models.Booking
+// @Router /api/bookings/approvals [get]
+func (h *BookingHandler) ListPendingApprovals(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	bookings, err := h.bookingService.ListPendingApprovals(r.Context(), userID, user.Role)
+	if errors.Is(err, services.ErrNotReviewer) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bookings)
+}
+
+// @Summary Approve booking
+// @Description Confirm a booking awaiting approval (admin or the resource's provider)
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Booking
+// @Router /api/bookings/{id}/approve [post]
+func (h *BookingHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, h.bookingService.Approve)
+}
+
+// @Summary Reject booking
+// @Description Decline a booking awaiting approval and free its seat (admin or the resource's provider)
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Booking
+// @Router /api/bookings/{id}/reject [post]
+func (h *BookingHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, h.bookingService.Reject)
+}
+
+func (h *BookingHandler) review(w http.ResponseWriter, r *http.Request, decide func(ctx context.Context, bookingID, reviewerID uuid.UUID, reviewerRole, reason string) (*models.Booking, error)) {
+	bookingID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(bookingID)
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// The reason is optional, so an empty body is fine
+	var req models.ReviewBookingRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+	}
+
+	booking, err := decide(r.Context(), id, userID, user.Role, req.Reason)
+	if errors.Is(err, services.ErrNotReviewer) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(booking)
+}
+
+// writeRuleViolations answers with 422 and the violated rules when booking
+// rules rejected the request, and reports whether it did.
+func writeRuleViolations(w http.ResponseWriter, err error) bool {
+	var ruleErr *services.BookingRuleError
+	if !errors.As(err, &ruleErr) {
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(models.RuleViolationResponse{
+		Error:      err.Error(),
+		Violations: ruleErr.Violations,
+	})
+	return true
+}
+
+// @Summary Get booking history
+// @Description Retrieve the recorded status transitions of a booking. Users see their own bookings' history, providers that of bookings on their resources, admins any
+// @Tags bookings
+// @Produce json
+// @Success 200 {array} models.BookingTransition
+// @Router /api/bookings/{id}/transitions [get]
+func (h *BookingHandler) GetTransitions(w http.ResponseWriter, r *http.Request) {
+	bookingID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(bookingID)
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	transitions, err := h.bookingService.GetTransitions(r.Context(), id, userID, user.Role)
+	if err != nil {
+		http.Error(w, "Booking not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transitions)
+}
+
+// @Summary Mark attendance
+// @Description Record whether a confirmed booking was used: checked_in (from 30 minutes before the start), no_show or completed (after the start). Admins and the resource's providers only
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Booking
+// @Router /api/bookings/{id}/attendance [post]
+func (h *BookingHandler) MarkAttendance(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.MarkAttendanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	booking, err := h.bookingService.MarkAttendance(r.Context(), id, userID, user.Role, req.Attendance)
+	if errors.Is(err, services.ErrNotReviewer) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(booking)
+}
+
+// @Summary Get my no-shows
+// @Description Count the current user's recent no-shows and whether they block new bookings
+// @Tags bookings
+// @Produce json
+// @Success 200 {object} models.NoShowSummary
+// @Router /api/bookings/no-shows [get]
+func (h *BookingHandler) GetNoShows(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	summary, err := h.bookingService.NoShowSummary(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// @Summary Get booking refunds
+// @Description List the refunds issued on a booking's payment, oldest first
+// @Tags bookings
+// @Produce json
+// @Success 200 {array} models.Refund
+// @Router /api/bookings/{id}/refunds [get]
+func (h *BookingHandler) GetRefunds(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	refunds, err := h.bookingService.GetRefunds(r.Context(), id, userID, user.Role)
+	if err != nil {
+		http.Error(w, "Booking not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(refunds)
+}
+
+// @Summary Issue a refund
+// @Description Refund amount, or everything not refunded yet, of a paid booking as a manual correction or goodwill gesture, whatever its status. The reason is recorded in the booking's history. Requires an Idempotency-Key header; a retry with the same key returns the refund already issued (admin only)
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Refund
+// @Router /api/bookings/{id}/refunds [post]
+func (h *BookingHandler) IssueRefund(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	if idempotencyKey == "" {
+		http.Error(w, "Idempotency-Key header is required", http.StatusBadRequest)
+		return
+	}
+
+	var req models.IssueRefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	refund, err := h.bookingService.IssueRefund(r.Context(), id, userID, idempotencyKey, &req)
+	if errors.Is(err, services.ErrNothingToRefund) || errors.Is(err, services.ErrRefundTooLarge) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, services.ErrPaymentsDisabled) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(refund)
+}
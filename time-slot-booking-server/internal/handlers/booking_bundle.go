@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time-slot-booking-server/internal/middleware"
+	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// @Summary Create booking bundle
+// @Description Book several resources, or members of pools, for the same interval atomically; required pools are added automatically
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.BookingBundleResponse
+// @Router /api/bookings/bundles [post]
+func (h *BookingHandler) CreateBundle(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateBundleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.bookingService.CreateBundle(r.Context(), userID, &req)
+	if errors.Is(err, services.ErrNoShowLimit) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if writeRuleViolations(w, err) {
+		return
+	}
+	if errors.Is(err, services.ErrInsufficientCredits) {
+		http.Error(w, err.Error(), http.StatusPaymentRequired)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary List booking bundles
+// @Description Retrieve the current user's booking bundles
+// @Tags bookings
+// @Produce json
+// @Success 200 {array} models.BookingBundle
+// @Router /api/bookings/bundles [get]
+func (h *BookingHandler) GetUserBundles(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	bundles, err := h.bookingService.GetUserBundles(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bundles)
+}
+
+// @Summary Get booking bundle
+// @Description Retrieve a booking bundle with its component bookings
+// @Tags bookings
+// @Produce json
+// @Success 200 {object} models.BookingBundleResponse
+// @Router /api/bookings/bundles/{id} [get]
+func (h *BookingHandler) GetBundle(w http.ResponseWriter, r *http.Request) {
+	bundleID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(bundleID)
+	if err != nil {
+		http.Error(w, "Invalid bundle ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	response, err := h.bookingService.GetBundle(r.Context(), id, userID)
+	if err != nil {
+		http.Error(w, "Bundle not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Cancel booking bundle
+// @Description Cancel a bundle and release every component booking
+// @Tags bookings
+// @Produce json
+// @Success 200 {array} models.Booking
+// @Router /api/bookings/bundles/{id}/cancel [post]
+func (h *BookingHandler) CancelBundle(w http.ResponseWriter, r *http.Request) {
+	bundleID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(bundleID)
+	if err != nil {
+		http.Error(w, "Invalid bundle ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	cancelled, err := h.bookingService.CancelBundle(r.Context(), id, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cancelled)
+}
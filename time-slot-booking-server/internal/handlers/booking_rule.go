@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type BookingRuleHandler struct {
+	bookingRuleService *services.BookingRuleService
+}
+
+func NewBookingRuleHandler(bookingRuleService *services.BookingRuleService) *BookingRuleHandler {
+	return &BookingRuleHandler{bookingRuleService: bookingRuleService}
+}
+
+// @Summary List booking rules
+// @Description Retrieve the rules limiting lead time, advance window and per-user quotas
+// @Tags booking-rules
+// @Produce json
+// @Success 200 {array} models.BookingRule
+// @Router /api/booking-rules [get]
+func (h *BookingRuleHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.bookingRuleService.GetAll(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
+
+// @Summary Create booking rule
+// @Description Limit bookings on a resource, a resource type or everything: min_notice_minutes, max_advance_days, max_active_bookings, max_hours_per_week, one_per_day (admin only)
+// @Tags booking-rules
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.BookingRule
+// @Router /api/booking-rules [post]
+func (h *BookingRuleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateBookingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.bookingRuleService.Create(r.Context(), &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
+
+// @Summary Update booking rule
+// @Description Replace the scope and limits of a booking rule (admin only)
+// @Tags booking-rules
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.BookingRule
+// @Router /api/booking-rules/{id} [put]
+func (h *BookingRuleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid rule ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateBookingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.bookingRuleService.Update(r.Context(), id, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// @Summary Delete booking rule
+// @Description Remove a booking rule (admin only)
+// @Tags booking-rules
+// @Success 204
+// @Router /api/booking-rules/{id} [delete]
+func (h *BookingRuleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid rule ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.bookingRuleService.Delete(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time-slot-booking-server/internal/middleware"
+	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// @Summary Create recurring booking series
+// @Description Book a time slot and its repeats under a recurrence rule (FREQ=WEEKLY or MONTHLY, INTERVAL, COUNT or UNTIL), reporting each occurrence
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.BookingSeriesResponse
+// @Failure 409 {object} models.BookingSeriesResponse
+// @Router /api/bookings/series [post]
+func (h *BookingHandler) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateBookingSeriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.bookingService.CreateSeries(r.Context(), userID, &req)
+	if errors.Is(err, services.ErrNoShowLimit) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, services.ErrSeriesConflict) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary List booking series
+// @Description Retrieve the current user's recurring booking series
+// @Tags bookings
+// @Produce json
+// @Success 200 {array} models.BookingSeries
+// @Router /api/bookings/series [get]
+func (h *BookingHandler) GetUserSeries(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	series, err := h.bookingService.GetUserSeries(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(series)
+}
+
+// @Summary Get booking series
+// @Description Retrieve a recurring booking series with its bookings
+// @Tags bookings
+// @Produce json
+// @Success 200 {object} models.BookingSeriesResponse
+// @Router /api/bookings/series/{id} [get]
+func (h *BookingHandler) GetSeries(w http.ResponseWriter, r *http.Request) {
+	seriesID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(seriesID)
+	if err != nil {
+		http.Error(w, "Invalid series ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	response, err := h.bookingService.GetSeries(r.Context(), id, userID)
+	if err != nil {
+		http.Error(w, "Series not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Cancel booking series
+// @Description Cancel one occurrence, an occurrence and all following ones, or every upcoming occurrence of a series
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Booking
+// @Router /api/bookings/series/{id}/cancel [post]
+func (h *BookingHandler) CancelSeries(w http.ResponseWriter, r *http.Request) {
+	seriesID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(seriesID)
+	if err != nil {
+		http.Error(w, "Invalid series ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CancelSeriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	cancelled, err := h.bookingService.CancelSeries(r.Context(), id, userID, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cancelled)
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time-slot-booking-server/internal/middleware"
+	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type CreditHandler struct {
+	creditService *services.CreditService
+}
+
+func NewCreditHandler(creditService *services.CreditService) *CreditHandler {
+	return &CreditHandler{creditService: creditService}
+}
+
+// @Summary List credit products
+// @Description Retrieve the prepaid packs on offer, such as session cards, and the resource types their credits pay for
+// @Tags credits
+// @Produce json
+// @Success 200 {array} models.CreditProduct
+// @Router /api/credit-products [get]
+func (h *CreditHandler) GetProducts(w http.ResponseWriter, r *http.Request) {
+	products, err := h.creditService.GetProducts(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(products)
+}
+
+// @Summary Create credit product
+// @Description Offer a pack of credits for resource_types (any type when empty), optionally expiring validity_days after purchase (admin only)
+// @Tags credits
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.CreditProduct
+// @Router /api/credit-products [post]
+func (h *CreditHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateCreditProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	product, err := h.creditService.CreateProduct(r.Context(), &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(product)
+}
+
+// @Summary Update credit product
+// @Description Replace a credit product; packs already sold keep their terms (admin only)
+// @Tags credits
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.CreditProduct
+// @Router /api/credit-products/{id} [put]
+func (h *CreditHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateCreditProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	product, err := h.creditService.UpdateProduct(r.Context(), id, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// @Summary Delete credit product
+// @Description Remove a credit product from sale; packs already sold stay usable (admin only)
+// @Tags credits
+// @Success 204
+// @Router /api/credit-products/{id} [delete]
+func (h *CreditHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.creditService.DeleteProduct(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Record credit purchase
+// @Description Record the sale of a credit product to a user and add its credits to their balance (admin only)
+// @Tags credits
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.CreditPack
+// @Router /api/credits/purchases [post]
+func (h *CreditHandler) RecordPurchase(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	adminID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.RecordCreditPurchaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	pack, err := h.creditService.RecordPurchase(r.Context(), adminID, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(pack)
+}
+
+// @Summary Get credit balance
+// @Description Retrieve the current user's spendable credits and the packs they are in, soonest to expire first
+// @Tags credits
+// @Produce json
+// @Success 200 {object} models.CreditBalance
+// @Router /api/credits [get]
+func (h *CreditHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	balance, err := h.creditService.GetBalance(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(balance)
+}
+
+// @Summary Get credit transactions
+// @Description Retrieve the current user's credits ledger, newest first: purchases, booking debits, credits returned or kept on cancellation, and expiries
+// @Tags credits
+// @Produce json
+// @Success 200 {array} models.CreditTransaction
+// @Router /api/credits/transactions [get]
+func (h *CreditHandler) GetTransactions(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	transactions, err := h.creditService.GetTransactions(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transactions)
+}
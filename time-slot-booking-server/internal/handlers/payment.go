@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time-slot-booking-server/internal/middleware"
+	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/payments"
+	"time-slot-booking-server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxWebhookSize bounds the webhook bodies read from payment providers.
+const maxWebhookSize = 64 << 10
+
+type PaymentHandler struct {
+	paymentService *services.PaymentService
+}
+
+func NewPaymentHandler(paymentService *services.PaymentService) *PaymentHandler {
+	return &PaymentHandler{paymentService: paymentService}
+}
+
+// @Summary Payment provider webhook
+// @Description Receive payment notifications from the payment provider. Authorized payments are captured and confirm their booking; failed payments expire it. Redelivered events are acknowledged without being applied again
+// @Tags payments
+// @Accept json
+// @Success 200
+// @Router /webhooks/payments [post]
+func (h *PaymentHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookSize))
+	if err != nil {
+		http.Error(w, "Invalid body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.paymentService.HandleWebhook(r.Context(), payload, r.Header)
+	if errors.Is(err, services.ErrPaymentsDisabled) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, payments.ErrInvalidSignature) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Get booking payments
+// @Description List the payments of a booking, newest first. The client_secret needed to pay is only shown to the booking's owner
+// @Tags payments
+// @Produce json
+// @Success 200 {array} models.Payment
+// @Router /api/bookings/{id}/payments [get]
+func (h *PaymentHandler) GetForBooking(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	paymentList, err := h.paymentService.GetForBooking(r.Context(), id, userID, user.Role)
+	if err != nil {
+		http.Error(w, "Booking not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(paymentList)
+}
+
+// @Summary Simulate a payment
+// @Description Settle the pending payment of a booking with the fake payment provider, as succeeded or failed, and apply the resulting webhook. Only available when PAYMENT_PROVIDER=fake
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Payment
+// @Router /api/bookings/{id}/payments/simulate [post]
+func (h *PaymentHandler) Simulate(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid booking ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.SimulatePaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Outcome != "succeeded" && req.Outcome != "failed" {
+		http.Error(w, "outcome must be succeeded or failed", http.StatusBadRequest)
+		return
+	}
+
+	payment, err := h.paymentService.Simulate(r.Context(), id, userID, user.Role, req.Outcome == "succeeded")
+	if errors.Is(err, services.ErrPaymentsDisabled) || errors.Is(err, services.ErrSimulateUnsupported) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payment)
+}
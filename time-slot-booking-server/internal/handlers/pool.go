@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type PoolHandler struct {
+	poolService *services.PoolService
+}
+
+func NewPoolHandler(poolService *services.PoolService) *PoolHandler {
+	return &PoolHandler{poolService: poolService}
+}
+
+// @Summary List resource pools
+// @Description Retrieve all resource pools
+// @Tags pools
+// @Produce json
+// @Success 200 {array} models.ResourcePool
+// @Router /api/pools [get]
+func (h *PoolHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	pools, err := h.poolService.GetAll(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pools)
+}
+
+// @Summary Get resource pool
+// @Description Retrieve a resource pool with its members
+// @Tags pools
+// @Produce json
+// @Success 200 {object} models.ResourcePool
+// @Router /api/pools/{id} [get]
+func (h *PoolHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid pool ID", http.StatusBadRequest)
+		return
+	}
+
+	pool, err := h.poolService.GetByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Pool not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pool)
+}
+
+// @Summary Create resource pool
+// @Description Create a pool of interchangeable resources with an assignment strategy: least_used, round_robin or priority (admin only)
+// @Tags pools
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.ResourcePool
+// @Router /api/pools [post]
+func (h *PoolHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateResourcePoolRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	pool, err := h.poolService.Create(r.Context(), &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(pool)
+}
+
+// @Summary Update resource pool
+// @Description Change a pool's name, description or strategy (admin only)
+// @Tags pools
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.ResourcePool
+// @Router /api/pools/{id} [put]
+func (h *PoolHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid pool ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateResourcePoolRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	pool, err := h.poolService.Update(r.Context(), id, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pool)
+}
+
+// @Summary Delete resource pool
+// @Description Delete a resource pool; its resources and bookings are kept (admin only)
+// @Tags pools
+// @Success 204
+// @Router /api/pools/{id} [delete]
+func (h *PoolHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid pool ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.poolService.Delete(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Add pool member
+// @Description Add a resource to a pool or change its priority (admin only)
+// @Tags pools
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.ResourcePoolMember
+// @Router /api/pools/{id}/members [post]
+func (h *PoolHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid pool ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.AddPoolMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	member, err := h.poolService.AddMember(r.Context(), id, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(member)
+}
+
+// @Summary Remove pool member
+// @Description Take a resource out of a pool (admin only)
+// @Tags pools
+// @Success 204
+// @Router /api/pools/{id}/members/{resourceId} [delete]
+func (h *PoolHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid pool ID", http.StatusBadRequest)
+		return
+	}
+
+	resourceID, err := uuid.Parse(chi.URLParam(r, "resourceId"))
+	if err != nil {
+		http.Error(w, "Invalid resource ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.poolService.RemoveMember(r.Context(), id, resourceID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
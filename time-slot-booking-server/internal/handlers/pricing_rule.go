@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type PricingRuleHandler struct {
+	pricingRuleService *services.PricingRuleService
+}
+
+func NewPricingRuleHandler(pricingRuleService *services.PricingRuleService) *PricingRuleHandler {
+	return &PricingRuleHandler{pricingRuleService: pricingRuleService}
+}
+
+// @Summary List pricing rules
+// @Description Retrieve the surcharges, discounts and per-seat rules that price bookings
+// @Tags pricing-rules
+// @Produce json
+// @Success 200 {array} models.PricingRule
+// @Router /api/pricing-rules [get]
+func (h *PricingRuleHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.pricingRuleService.GetAll(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
+
+// @Summary Create pricing rule
+// @Description Adjust prices on a resource, a resource type or everything by kind: peak, off_peak, weekend, member, early_bird, last_minute or per_seat (admin only)
+// @Tags pricing-rules
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.PricingRule
+// @Router /api/pricing-rules [post]
+func (h *PricingRuleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreatePricingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.pricingRuleService.Create(r.Context(), &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
+
+// @Summary Update pricing rule
+// @Description Replace a pricing rule; existing bookings keep their price (admin only)
+// @Tags pricing-rules
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.PricingRule
+// @Router /api/pricing-rules/{id} [put]
+func (h *PricingRuleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid rule ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreatePricingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.pricingRuleService.Update(r.Context(), id, &req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// @Summary Delete pricing rule
+// @Description Remove a pricing rule (admin only)
+// @Tags pricing-rules
+// @Success 204
+// @Router /api/pricing-rules/{id} [delete]
+func (h *PricingRuleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid rule ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.pricingRuleService.Delete(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -155,3 +155,171 @@ func (h *ResourceHandler) GetByType(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resources)
 }
+
+// @Summary List resource providers
+// @Description List the providers who may approve bookings on a resource (admin only)
+// @Tags resources
+// @Produce json
+// @Success 200 {array} models.ResourceProvider
+// @Router /api/resources/{id}/providers [get]
+func (h *ResourceHandler) ListProviders(w http.ResponseWriter, r *http.Request) {
+	resourceID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(resourceID)
+	if err != nil {
+		http.Error(w, "Invalid resource ID", http.StatusBadRequest)
+		return
+	}
+
+	providers, err := h.resourceService.ListProviders(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(providers)
+}
+
+// @Summary Add resource provider
+// @Description Allow a provider to approve bookings on a resource (admin only)
+// @Tags resources
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.ResourceProvider
+// @Router /api/resources/{id}/providers [post]
+func (h *ResourceHandler) AddProvider(w http.ResponseWriter, r *http.Request) {
+	resourceID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(resourceID)
+	if err != nil {
+		http.Error(w, "Invalid resource ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.AddResourceProviderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	provider, err := h.resourceService.AddProvider(r.Context(), id, req.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(provider)
+}
+
+// @Summary Remove resource provider
+// @Description Stop a provider from approving bookings on a resource (admin only)
+// @Tags resources
+// @Success 204
+// @Router /api/resources/{id}/providers/{userId} [delete]
+func (h *ResourceHandler) RemoveProvider(w http.ResponseWriter, r *http.Request) {
+	resourceID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(resourceID)
+	if err != nil {
+		http.Error(w, "Invalid resource ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := uuid.Parse(chi.URLParam(r, "userId"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.resourceService.RemoveProvider(r.Context(), id, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary List resource dependencies
+// @Description List the pools a resource needs a member of whenever it is booked (admin only)
+// @Tags resources
+// @Produce json
+// @Success 200 {array} models.ResourceDependency
+// @Router /api/resources/{id}/dependencies [get]
+func (h *ResourceHandler) ListDependencies(w http.ResponseWriter, r *http.Request) {
+	resourceID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(resourceID)
+	if err != nil {
+		http.Error(w, "Invalid resource ID", http.StatusBadRequest)
+		return
+	}
+
+	dependencies, err := h.resourceService.ListDependencies(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dependencies)
+}
+
+// @Summary Add resource dependency
+// @Description Require bookings of a resource to include a member of a pool, so it can only be booked in a bundle (admin only)
+// @Tags resources
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.ResourceDependency
+// @Router /api/resources/{id}/dependencies [post]
+func (h *ResourceHandler) AddDependency(w http.ResponseWriter, r *http.Request) {
+	resourceID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(resourceID)
+	if err != nil {
+		http.Error(w, "Invalid resource ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.AddResourceDependencyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	dependency, err := h.resourceService.AddDependency(r.Context(), id, req.PoolID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dependency)
+}
+
+// @Summary Remove resource dependency
+// @Description Stop requiring a pool member with bookings of a resource (admin only)
+// @Tags resources
+// @Success 204
+// @Router /api/resources/{id}/dependencies/{poolId} [delete]
+func (h *ResourceHandler) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	resourceID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(resourceID)
+	if err != nil {
+		http.Error(w, "Invalid resource ID", http.StatusBadRequest)
+		return
+	}
+
+	poolID, err := uuid.Parse(chi.URLParam(r, "poolId"))
+	if err != nil {
+		http.Error(w, "Invalid pool ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.resourceService.RemoveDependency(r.Context(), id, poolID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
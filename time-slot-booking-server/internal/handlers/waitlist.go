@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time-slot-booking-server/internal/middleware"
+	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type WaitlistHandler struct {
+	waitlistService *services.WaitlistService
+}
+
+func NewWaitlistHandler(waitlistService *services.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{waitlistService: waitlistService}
+}
+
+// @Summary List waitlist positions
+// @Description Retrieve the current user's waitlist entries with their queue positions
+// @Tags waitlist
+// @Produce json
+// @Success 200 {array} models.WaitlistPosition
+// @Router /api/waitlist [get]
+func (h *WaitlistHandler) List(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	positions, err := h.waitlistService.ListForUser(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(positions)
+}
+
+// @Summary Join waitlist
+// @Description Join the waitlist of a fully booked time slot
+// @Tags waitlist
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.WaitlistEntry
+// @Router /api/waitlist [post]
+func (h *WaitlistHandler) Join(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.JoinWaitlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.waitlistService.Join(r.Context(), userID, &req)
+	if errors.Is(err, services.ErrNoShowLimit) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+// @Summary Leave waitlist
+// @Description Leave a waitlist, declining any open offer
+// @Tags waitlist
+// @Success 204
+// @Router /api/waitlist/{id} [delete]
+func (h *WaitlistHandler) Leave(w http.ResponseWriter, r *http.Request) {
+	entryID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(entryID)
+	if err != nil {
+		http.Error(w, "Invalid waitlist entry ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	err = h.waitlistService.Leave(r.Context(), id, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Claim waitlist offer
+// @Description Book the seat offered to the current user before the offer expires
+// @Tags waitlist
+// @Produce json
+// @Success 201 {object} models.Booking
+// @Router /api/waitlist/{id}/claim [post]
+func (h *WaitlistHandler) Claim(w http.ResponseWriter, r *http.Request) {
+	entryID := chi.URLParam(r, "id")
+	id, err := uuid.Parse(entryID)
+	if err != nil {
+		http.Error(w, "Invalid waitlist entry ID", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUser(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	booking, err := h.waitlistService.Claim(r.Context(), id, userID)
+	if writeRuleViolations(w, err) {
+		return
+	}
+	if errors.Is(err, services.ErrNoShowLimit) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(booking)
+}
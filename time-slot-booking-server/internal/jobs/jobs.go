@@ -0,0 +1,57 @@
+// Package jobs runs periodic background maintenance tasks inside the server.
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"time-slot-booking-server/internal/logger"
+)
+
+// Job is a task run on a fixed interval.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Start runs each job once immediately and then on its interval until ctx is
+// cancelled. Errors are logged and do not stop the job. Jobs with a
+// non-positive interval are disabled.
+func Start(ctx context.Context, jobs ...Job) {
+	for _, job := range jobs {
+		if job.Interval <= 0 {
+			logger.Warn().
+				Str("job", job.Name).
+				Msg("Background job disabled")
+			continue
+		}
+		go run(ctx, job)
+	}
+}
+
+func run(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+			logger.Error().
+				Str("job", job.Name).
+				Err(err).
+				Msg("Background job failed")
+		} else {
+			logger.Debug().
+				Str("job", job.Name).
+				Dur("duration", time.Since(start)).
+				Msg("Background job completed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
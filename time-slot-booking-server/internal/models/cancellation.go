@@ -0,0 +1,113 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CancellationPolicy decides what it costs to cancel a booking on a
+// resource. Cancelling at least FreeCancelHours before the start is free;
+// later cancellations pay the fee of the tightest tier that covers the time
+// left. Bookings cannot be cancelled once they have started.
+type CancellationPolicy struct {
+	FreeCancelHours float64            `json:"free_cancel_hours"`
+	FeeTiers        []CancellationTier `json:"fee_tiers,omitempty"`
+}
+
+// CancellationTier charges FeePercent of the booking amount for
+// cancellations made less than WithinHours before the start.
+type CancellationTier struct {
+	WithinHours float64 `json:"within_hours"`
+	FeePercent  float64 `json:"fee_percent"`
+}
+
+func (p *CancellationPolicy) Validate() error {
+	if p.FreeCancelHours < 0 {
+		return fmt.Errorf("free_cancel_hours must not be negative")
+	}
+	for _, tier := range p.FeeTiers {
+		if tier.WithinHours <= 0 {
+			return fmt.Errorf("within_hours must be positive")
+		}
+		if tier.WithinHours > p.FreeCancelHours {
+			return fmt.Errorf("fee tier within %g hours overlaps the free cancellation window", tier.WithinHours)
+		}
+		if tier.FeePercent < 0 || tier.FeePercent > 100 {
+			return fmt.Errorf("fee_percent must be between 0 and 100")
+		}
+	}
+	return nil
+}
+
+// FeePercent returns the share of the booking amount charged for
+// cancelling with left time to go before the start. A nil policy makes
+// every cancellation before the start free.
+func (p *CancellationPolicy) FeePercent(left time.Duration) float64 {
+	if p == nil || left.Hours() >= p.FreeCancelHours {
+		return 0
+	}
+
+	tiers := append([]CancellationTier(nil), p.FeeTiers...)
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].WithinHours < tiers[j].WithinHours })
+
+	for _, tier := range tiers {
+		if left.Hours() < tier.WithinHours {
+			return tier.FeePercent
+		}
+	}
+	return 0
+}
+
+// CancellationQuote is what cancelling a booking would cost now, or what it
+// did cost once the booking is cancelled. Fee and Refund are in the
+// booking's currency, and nil when the booking has no price.
+type CancellationQuote struct {
+	BookingID        uuid.UUID `json:"booking_id"`
+	Allowed          bool      `json:"allowed"`
+	Reason           string    `json:"reason,omitempty"`
+	HoursBeforeStart float64   `json:"hours_before_start"`
+	FeePercent       float64   `json:"fee_percent"`
+	Fee              *Money    `json:"fee"`
+	Refund           *Money    `json:"refund"`
+	// CreditsReturned is what goes back to the pack of a booking paid with
+	// credits; late cancellations keep them
+	CreditsReturned int                 `json:"credits_returned,omitempty"`
+	Override        bool                `json:"override,omitempty"`
+	Policy          *CancellationPolicy `json:"policy,omitempty"`
+}
+
+// QuoteCancellation prices cancelling a booking of amount starting at start
+// under the policy at now. With override the policy is waived: the
+// cancellation is free and allowed even after the start.
+func QuoteCancellation(policy *CancellationPolicy, amount *Money, start, now time.Time, override bool) *CancellationQuote {
+	left := start.Sub(now)
+	quote := &CancellationQuote{
+		Allowed:          true,
+		HoursBeforeStart: math.Round(left.Hours()*100) / 100,
+		Override:         override,
+		Policy:           policy,
+	}
+
+	switch {
+	case override:
+	case left <= 0:
+		quote.Allowed = false
+		quote.Reason = "booking has already started"
+	default:
+		quote.FeePercent = policy.FeePercent(left)
+	}
+
+	if amount != nil {
+		fee := amount.Percent(quote.FeePercent)
+		quote.Fee = &fee
+		if quote.Allowed {
+			refund := Money{Amount: amount.Amount - fee.Amount, Currency: amount.Currency}
+			quote.Refund = &refund
+		}
+	}
+	return quote
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// CreditProduct is a prepaid pack on sale, such as a 10-session card. Each
+// credit pays for one seat of a booking on a resource of one of
+// ResourceTypes, or of any type when it is empty.
+type CreditProduct struct {
+	bun.BaseModel `bun:"credit_products"`
+	ID            uuid.UUID `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
+	Name          string    `json:"name" db:"name" bun:"name,notnull"`
+	Description   string    `json:"description,omitempty" db:"description" bun:"description,nullzero"`
+	ResourceTypes []string  `json:"resource_types" db:"resource_types" bun:"resource_types,array,notnull"`
+	Credits       int       `json:"credits" db:"credits" bun:"credits,notnull"`
+	Price         *Money    `json:"price,omitempty" db:"price" bun:"price"`
+	// ValidityDays is how long packs stay usable after purchase; nil never
+	// expires
+	ValidityDays *int      `json:"validity_days,omitempty" db:"validity_days" bun:"validity_days"`
+	Active       bool      `json:"active" db:"active" bun:"active,notnull,default:true"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at" bun:"updated_at,notnull,default:now()"`
+}
+
+type CreateCreditProductRequest struct {
+	Name          string   `json:"name" validate:"required"`
+	Description   string   `json:"description"`
+	ResourceTypes []string `json:"resource_types"`
+	Credits       int      `json:"credits" validate:"min=1"`
+	Price         *Money   `json:"price"`
+	ValidityDays  *int     `json:"validity_days" validate:"omitempty,min=1"`
+	Active        *bool    `json:"active"`
+}
+
+// Validate checks the product's terms.
+func (p *CreditProduct) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if p.Credits <= 0 {
+		return fmt.Errorf("credits must be positive")
+	}
+	if p.ValidityDays != nil && *p.ValidityDays <= 0 {
+		return fmt.Errorf("validity_days must be positive")
+	}
+	for _, resourceType := range p.ResourceTypes {
+		if resourceType == "" {
+			return fmt.Errorf("resource_types must not contain empty names")
+		}
+	}
+	if p.Price != nil {
+		return p.Price.Validate()
+	}
+	return nil
+}
+
+// CreditPack is a product bought by a user, with its terms as sold.
+type CreditPack struct {
+	bun.BaseModel `bun:"credit_packs"`
+	ID            uuid.UUID  `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
+	UserID        uuid.UUID  `json:"user_id" db:"user_id" bun:"user_id,notnull"`
+	ProductID     *uuid.UUID `json:"product_id,omitempty" db:"product_id" bun:"product_id"`
+	Name          string     `json:"name" db:"name" bun:"name,notnull"`
+	ResourceTypes []string   `json:"resource_types" db:"resource_types" bun:"resource_types,array,notnull"`
+	Credits       int        `json:"credits" db:"credits" bun:"credits,notnull"`
+	Remaining     int        `json:"remaining" db:"remaining" bun:"remaining,notnull"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty" db:"expires_at" bun:"expires_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+}
+
+// CreditTransaction is one entry of a user's credits ledger: a purchase,
+// the debit of a booking, credits given back on cancellation, credits kept
+// for a late cancellation (zero credits) or the expiry of what was left in
+// a pack.
+type CreditTransaction struct {
+	bun.BaseModel `bun:"credit_transactions"`
+	ID            uuid.UUID  `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
+	UserID        uuid.UUID  `json:"user_id" db:"user_id" bun:"user_id,notnull"`
+	PackID        uuid.UUID  `json:"pack_id" db:"pack_id" bun:"pack_id,notnull"`
+	BookingID     *uuid.UUID `json:"booking_id,omitempty" db:"booking_id" bun:"booking_id"`
+	Kind          string     `json:"kind" db:"kind" bun:"kind,notnull" validate:"oneof=purchase debit refund forfeit expiry"`
+	Credits       int        `json:"credits" db:"credits" bun:"credits,notnull"`
+	Note          string     `json:"note,omitempty" db:"note" bun:"note,nullzero"`
+	ActorID       *uuid.UUID `json:"actor_id,omitempty" db:"actor_id" bun:"actor_id"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+}
+
+// RecordCreditPurchaseRequest records the sale of a product to a user.
+type RecordCreditPurchaseRequest struct {
+	UserID    uuid.UUID `json:"user_id" validate:"required"`
+	ProductID uuid.UUID `json:"product_id" validate:"required"`
+	Note      string    `json:"note"`
+}
+
+// CreditBalance is what a user can still spend: the total of their usable
+// packs and the packs themselves, soonest to expire first.
+type CreditBalance struct {
+	Balance int          `json:"balance"`
+	Packs   []CreditPack `json:"packs"`
+}
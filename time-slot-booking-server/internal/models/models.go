@@ -19,19 +19,20 @@ type User struct {
 }
 
 type AppUser struct {
-	bun.BaseModel  `bun:"app_users"`
-	ID             uuid.UUID `json:"id" bun:",pk,default:gen_random_uuid()"`
-	Email          []byte    `json:"email" bun:"email,notnull"`         // Encrypted
-	Name           []byte    `json:"name" bun:"name,notnull"`           // Encrypted
-	Provider       string    `json:"provider" bun:"provider,notnull"`
-	ProviderUserID []byte    `json:"provider_user_id" bun:"provider_user_id,notnull"` // Encrypted
-	ProviderUserHash string  `json:"-" bun:"provider_user_hash,notnull,unique"`       // SHA256 Hash for lookup
-	AccessToken    []byte    `json:"access_token" bun:"access_token"`   // Encrypted
-	RefreshToken   []byte    `json:"refresh_token" bun:"refresh_token"` // Encrypted
-	TokenExpiresAt time.Time `json:"token_expires_at" bun:"token_expires_at"`
-	Role           string    `json:"role" bun:"role,notnull,default:'customer'"`
-	CreatedAt      time.Time `json:"created_at" bun:"created_at,notnull,default:now()"`
-	UpdatedAt      time.Time `json:"updated_at" bun:"updated_at,notnull,default:now()"`
+	bun.BaseModel    `bun:"app_users"`
+	ID               uuid.UUID `json:"id" bun:",pk,default:gen_random_uuid()"`
+	Email            []byte    `json:"email" bun:"email,notnull"` // Encrypted
+	Name             []byte    `json:"name" bun:"name,notnull"`   // Encrypted
+	Provider         string    `json:"provider" bun:"provider,notnull"`
+	ProviderUserID   []byte    `json:"provider_user_id" bun:"provider_user_id,notnull"` // Encrypted
+	ProviderUserHash string    `json:"-" bun:"provider_user_hash,notnull,unique"`       // SHA256 Hash for lookup
+	AccessToken      []byte    `json:"access_token" bun:"access_token"`                 // Encrypted
+	RefreshToken     []byte    `json:"refresh_token" bun:"refresh_token"`               // Encrypted
+	TokenExpiresAt   time.Time `json:"token_expires_at" bun:"token_expires_at"`
+	Role             string    `json:"role" bun:"role,notnull,default:'customer'"`
+	IsMember         bool      `json:"is_member" bun:"is_member,notnull,default:false"`
+	CreatedAt        time.Time `json:"created_at" bun:"created_at,notnull,default:now()"`
+	UpdatedAt        time.Time `json:"updated_at" bun:"updated_at,notnull,default:now()"`
 }
 
 // DecryptedAppUser is used for the application logic
@@ -42,19 +43,39 @@ type DecryptedAppUser struct {
 	Provider       string    `json:"provider"`
 	ProviderUserID string    `json:"provider_user_id"`
 	Role           string    `json:"role"`
+	IsMember       bool      `json:"is_member"`
 }
 
 type Resource struct {
-	bun.BaseModel  `bun:"resources"`
-	ID             uuid.UUID              `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
-	Name           string                 `json:"name" db:"name" bun:"name,notnull"`
-	Type           string                 `json:"type" db:"type" bun:"type,notnull" validate:"oneof=doctor court facility"`
-	Description    string                 `json:"description" db:"description" bun:"description"`
-	Location       string                 `json:"location" db:"location" bun:"location"`
-	Capacity       int                    `json:"capacity" db:"capacity" bun:"capacity,notnull,default:1"`
-	OperatingHours map[string]interface{} `json:"operating_hours" db:"operating_hours" bun:"operating_hours"`
-	CreatedAt      time.Time              `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
-	UpdatedAt      time.Time              `json:"updated_at" db:"updated_at" bun:"updated_at,notnull,default:now()"`
+	bun.BaseModel      `bun:"resources"`
+	ID                 uuid.UUID           `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
+	Name               string              `json:"name" db:"name" bun:"name,notnull"`
+	Type               string              `json:"type" db:"type" bun:"type,notnull" validate:"oneof=doctor court facility"`
+	Description        string              `json:"description" db:"description" bun:"description"`
+	Location           string              `json:"location" db:"location" bun:"location"`
+	Tags               []string            `json:"tags" db:"tags" bun:"tags,array,notnull,default:'{}'"`
+	Capacity           int                 `json:"capacity" db:"capacity" bun:"capacity,notnull,default:1"`
+	Currency           string              `json:"currency" db:"currency" bun:"currency,notnull,default:'USD'"`
+	OperatingHours     *OperatingHours     `json:"operating_hours" db:"operating_hours" bun:"operating_hours,type:jsonb"`
+	TimeZone           string              `json:"time_zone" db:"time_zone" bun:"time_zone,notnull,default:'UTC'"`
+	RequiresApproval   bool                `json:"requires_approval" db:"requires_approval" bun:"requires_approval,notnull,default:false"`
+	BookingMode        string              `json:"booking_mode" db:"booking_mode" bun:"booking_mode,notnull,default:'slots'" validate:"oneof=slots interval"`
+	IntervalRules      *IntervalRules      `json:"interval_rules,omitempty" db:"interval_rules" bun:"interval_rules,type:jsonb"`
+	CancellationPolicy *CancellationPolicy `json:"cancellation_policy,omitempty" db:"cancellation_policy" bun:"cancellation_policy,type:jsonb"`
+	CreatedAt          time.Time           `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+	UpdatedAt          time.Time           `json:"updated_at" db:"updated_at" bun:"updated_at,notnull,default:now()"`
+}
+
+// TimeLocation returns the resource's time zone, falling back to UTC.
+func (r *Resource) TimeLocation() *time.Location {
+	if r.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(r.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
 }
 
 type TimeSlot struct {
@@ -64,39 +85,144 @@ type TimeSlot struct {
 	StartTime     time.Time `json:"start_time" db:"start_time" bun:"start_time,notnull" validate:"required"`
 	EndTime       time.Time `json:"end_time" db:"end_time" bun:"end_time,notnull" validate:"required"`
 	Capacity      int       `json:"capacity" db:"capacity" bun:"capacity,notnull,default:1"`
+	BookedCount   int       `json:"booked_count" db:"booked_count" bun:"booked_count,notnull,default:0"`
 	IsAvailable   bool      `json:"is_available" db:"is_available" bun:"is_available,notnull,default:true"`
-	Price         *float64  `json:"price" db:"price" bun:"price"`
-	CreatedAt     time.Time `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+	// IsDisabled marks a slot closed by an admin; IsAvailable is also false
+	// while the slot is full
+	IsDisabled bool      `json:"is_disabled" db:"is_disabled" bun:"is_disabled,notnull,default:false"`
+	Price      *Money    `json:"price" db:"price" bun:"price"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
 }
 
 type Booking struct {
 	bun.BaseModel `bun:"bookings"`
-	ID            uuid.UUID `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
-	UserID        uuid.UUID `json:"user_id" db:"user_id" bun:"user_id,notnull" validate:"required"`
-	ResourceID    uuid.UUID `json:"resource_id" db:"resource_id" bun:"resource_id,notnull" validate:"required"`
-	TimeSlotID    uuid.UUID `json:"time_slot_id" db:"time_slot_id" bun:"time_slot_id,notnull" validate:"required"`
-	Status        string    `json:"status" db:"status" bun:"status,notnull,default:'confirmed'" validate:"oneof=pending confirmed cancelled"`
-	Notes         string    `json:"notes" db:"notes" bun:"notes"`
-	TotalAmount   *float64  `json:"total_amount" db:"total_amount" bun:"total_amount"`
-	CreatedAt     time.Time `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
-	UpdatedAt     time.Time `json:"updated_at" db:"updated_at" bun:"updated_at,notnull,default:now()"`
+	ID            uuid.UUID  `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
+	UserID        uuid.UUID  `json:"user_id" db:"user_id" bun:"user_id,notnull" validate:"required"`
+	ResourceID    uuid.UUID  `json:"resource_id" db:"resource_id" bun:"resource_id,notnull" validate:"required"`
+	TimeSlotID    *uuid.UUID `json:"time_slot_id,omitempty" db:"time_slot_id" bun:"time_slot_id"`
+	StartTime     time.Time  `json:"start_time" db:"start_time" bun:"start_time"`
+	EndTime       time.Time  `json:"end_time" db:"end_time" bun:"end_time"`
+	Status        string     `json:"status" db:"status" bun:"status,notnull,default:'confirmed'" validate:"oneof=pending confirmed cancelled rejected expired"`
+	Notes         string     `json:"notes" db:"notes" bun:"notes"`
+	TotalAmount   *Money     `json:"total_amount" db:"total_amount" bun:"total_amount"`
+	PartySize     int        `json:"party_size" db:"party_size" bun:"party_size,notnull,default:1"`
+	// HoldExpiresAt is set on pending bookings that hold a seat during checkout
+	HoldExpiresAt *time.Time        `json:"hold_expires_at,omitempty" db:"hold_expires_at" bun:"hold_expires_at"`
+	SeriesID      *uuid.UUID        `json:"series_id,omitempty" db:"series_id" bun:"series_id"`
+	PoolID        *uuid.UUID        `json:"pool_id,omitempty" db:"pool_id" bun:"pool_id"`
+	BundleID      *uuid.UUID        `json:"bundle_id,omitempty" db:"bundle_id" bun:"bundle_id"`
+	Attendees     []BookingAttendee `json:"attendees,omitempty" bun:"-"`
+	// PriceBreakdown itemises TotalAmount as quoted when the booking was made
+	PriceBreakdown []PriceLine `json:"price_breakdown,omitempty" db:"price_breakdown" bun:"price_breakdown,type:jsonb"`
+	// Set on cancellation: the fee charged, the amount due back and the
+	// policy they were worked out under
+	CancellationFee    *Money              `json:"cancellation_fee,omitempty" db:"cancellation_fee" bun:"cancellation_fee"`
+	RefundAmount       *Money              `json:"refund_amount,omitempty" db:"refund_amount" bun:"refund_amount"`
+	CancellationPolicy *CancellationPolicy `json:"cancellation_policy,omitempty" db:"cancellation_policy" bun:"cancellation_policy,type:jsonb"`
+	// Attendance is recorded by providers, or as no_show by the sweeper
+	Attendance         string     `json:"attendance,omitempty" db:"attendance" bun:"attendance,nullzero" validate:"omitempty,oneof=checked_in no_show completed"`
+	AttendanceMarkedAt *time.Time `json:"attendance_marked_at,omitempty" db:"attendance_marked_at" bun:"attendance_marked_at"`
+	// PaymentStatus is awaiting_payment while a priced booking is unpaid and
+	// paid once its payment succeeds; Payment is returned on creation
+	PaymentStatus string   `json:"payment_status,omitempty" db:"payment_status" bun:"payment_status,nullzero" validate:"omitempty,oneof=awaiting_payment paid"`
+	Payment       *Payment `json:"payment,omitempty" bun:"-"`
+	// Refund is returned by the call that refunded the booking
+	Refund *Refund `json:"refund,omitempty" bun:"-"`
+	// CreditsUsed seats were paid for with credits from CreditPackID instead
+	// of money
+	CreditPackID *uuid.UUID `json:"credit_pack_id,omitempty" db:"credit_pack_id" bun:"credit_pack_id"`
+	CreditsUsed  int        `json:"credits_used,omitempty" db:"credits_used" bun:"credits_used,notnull,default:0"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at" bun:"updated_at,notnull,default:now()"`
 }
 
 // API Request/Response models
 type CreateResourceRequest struct {
-	Name           string                 `json:"name" validate:"required"`
-	Type           string                 `json:"type" validate:"required,oneof=doctor court facility"`
-	Description    string                 `json:"description"`
-	Location       string                 `json:"location"`
-	Capacity       int                    `json:"capacity" validate:"min=1"`
-	OperatingHours map[string]interface{} `json:"operating_hours"`
+	Name               string              `json:"name" validate:"required"`
+	Type               string              `json:"type" validate:"required,oneof=doctor court facility"`
+	Description        string              `json:"description"`
+	Location           string              `json:"location"`
+	Tags               []string            `json:"tags"`
+	Capacity           int                 `json:"capacity" validate:"min=1"`
+	Currency           string              `json:"currency"`
+	OperatingHours     *OperatingHours     `json:"operating_hours"`
+	TimeZone           string              `json:"time_zone"`
+	RequiresApproval   bool                `json:"requires_approval"`
+	BookingMode        string              `json:"booking_mode" validate:"omitempty,oneof=slots interval"`
+	IntervalRules      *IntervalRules      `json:"interval_rules"`
+	CancellationPolicy *CancellationPolicy `json:"cancellation_policy"`
 }
 
+// CreateBookingRequest books either a time slot or, on resources in
+// interval booking mode, the free-form range StartTime to EndTime. With
+// PoolID instead of ResourceID, the server picks a pool member that has a
+// free slot from StartTime to EndTime. PartySize seats are taken, one
+// when unset.
 type CreateBookingRequest struct {
-	UserID     uuid.UUID `json:"user_id" validate:"required"`
-	ResourceID uuid.UUID `json:"resource_id" validate:"required"`
+	UserID     uuid.UUID         `json:"user_id" validate:"required"`
+	ResourceID uuid.UUID         `json:"resource_id"`
+	PoolID     *uuid.UUID        `json:"pool_id"`
+	TimeSlotID uuid.UUID         `json:"time_slot_id"`
+	StartTime  *time.Time        `json:"start_time"`
+	EndTime    *time.Time        `json:"end_time"`
+	Notes      string            `json:"notes"`
+	PartySize  int               `json:"party_size" validate:"omitempty,min=1"`
+	Attendees  []AttendeeRequest `json:"attendees"`
+	// QuotedTotal, when set, makes the booking fail if the price is no
+	// longer the one the user was quoted
+	QuotedTotal *Money `json:"quoted_total"`
+	// PayWithCredits pays one credit per seat from the user's packs instead
+	// of money
+	PayWithCredits bool `json:"pay_with_credits"`
+}
+
+// BookingAttendee names one member of a booking's party.
+type BookingAttendee struct {
+	bun.BaseModel `bun:"booking_attendees"`
+	ID            uuid.UUID `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
+	BookingID     uuid.UUID `json:"booking_id" db:"booking_id" bun:"booking_id,notnull"`
+	Name          string    `json:"name" db:"name" bun:"name,notnull"`
+	Email         string    `json:"email,omitempty" db:"email" bun:"email"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+}
+
+type AttendeeRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email"`
+}
+
+// UpdatePartyRequest shrinks a booking's party. Attendees, when given,
+// replace the current list.
+type UpdatePartyRequest struct {
+	PartySize int               `json:"party_size" validate:"required,min=1"`
+	Attendees []AttendeeRequest `json:"attendees"`
+}
+
+// CancelBookingRequest cancels a booking. Override, for admins only, waives
+// the resource's cancellation policy. When AcceptFee is set the
+// cancellation only goes through if the fee does not exceed it, so a
+// previewed fee cannot grow before the user confirms.
+type CancelBookingRequest struct {
+	Override  bool   `json:"override"`
+	AcceptFee *Money `json:"accept_fee"`
+	Reason    string `json:"reason"`
+}
+
+type MarkAttendanceRequest struct {
+	Attendance string `json:"attendance" validate:"required,oneof=checked_in no_show completed"`
+}
+
+// NoShowSummary reports a user's recent no-shows against the limit that
+// blocks new bookings. Limit 0 means no-shows never block booking.
+type NoShowSummary struct {
+	Count      int  `json:"count"`
+	Limit      int  `json:"limit"`
+	WindowDays int  `json:"window_days"`
+	Restricted bool `json:"restricted"`
+}
+
+type RescheduleBookingRequest struct {
 	TimeSlotID uuid.UUID `json:"time_slot_id" validate:"required"`
-	Notes      string    `json:"notes"`
 }
 
 type AvailabilityRequest struct {
@@ -105,9 +231,332 @@ type AvailabilityRequest struct {
 }
 
 type AvailabilityResponse struct {
+	TimeZone  string     `json:"time_zone"`
 	TimeSlots []TimeSlot `json:"time_slots"`
 }
 
+// SlotSearchQuery filters free time slots across resources. Zero values
+// leave a filter off; PartySize counts as 1 when unset.
+type SlotSearchQuery struct {
+	ResourceType string
+	Tags         []string
+	Location     string
+	From         time.Time
+	To           time.Time
+	MinMinutes   int
+	PartySize    int
+	Limit        int
+	Offset       int
+}
+
+// SlotSearchResult is a free time slot with the resource it belongs to.
+type SlotSearchResult struct {
+	TimeSlot       `bun:",extend"`
+	ResourceName   string   `json:"resource_name" bun:"resource_name"`
+	ResourceType   string   `json:"resource_type" bun:"resource_type"`
+	Location       string   `json:"location" bun:"location"`
+	Tags           []string `json:"tags" bun:"tags,array"`
+	TimeZone       string   `json:"time_zone" bun:"time_zone"`
+	SeatsAvailable int      `json:"seats_available" bun:"seats_available"`
+	TotalCount     int      `json:"-" bun:"total_count"`
+}
+
+type SlotSearchResponse struct {
+	Results    []SlotSearchResult `json:"results"`
+	Total      int                `json:"total"`
+	Limit      int                `json:"limit"`
+	Offset     int                `json:"offset"`
+	NextOffset *int               `json:"next_offset,omitempty"`
+}
+
 type TimeSlotListResponse struct {
 	TimeSlots []TimeSlot `json:"time_slots"`
 }
+
+// Catalog is the portable snapshot of resources and their time slots used by
+// the export and import commands.
+type Catalog struct {
+	Resources []Resource `json:"resources"`
+	TimeSlots []TimeSlot `json:"time_slots"`
+}
+
+// Blackout closes a time window for every resource, for all resources of a
+// type, or for a single resource.
+type Blackout struct {
+	bun.BaseModel `bun:"blackouts"`
+	ID            uuid.UUID  `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
+	Scope         string     `json:"scope" db:"scope" bun:"scope,notnull" validate:"oneof=global resource_type resource"`
+	ResourceType  string     `json:"resource_type,omitempty" db:"resource_type" bun:"resource_type,nullzero"`
+	ResourceID    *uuid.UUID `json:"resource_id,omitempty" db:"resource_id" bun:"resource_id"`
+	StartsAt      time.Time  `json:"starts_at" db:"starts_at" bun:"starts_at,notnull" validate:"required"`
+	EndsAt        time.Time  `json:"ends_at" db:"ends_at" bun:"ends_at,notnull" validate:"required"`
+	Reason        string     `json:"reason" db:"reason" bun:"reason"`
+	CreatedBy     *uuid.UUID `json:"created_by,omitempty" db:"created_by" bun:"created_by"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+}
+
+type CreateBlackoutRequest struct {
+	Scope          string     `json:"scope" validate:"omitempty,oneof=global resource_type resource"`
+	ResourceType   string     `json:"resource_type"`
+	ResourceID     *uuid.UUID `json:"resource_id"`
+	StartsAt       time.Time  `json:"starts_at" validate:"required"`
+	EndsAt         time.Time  `json:"ends_at" validate:"required"`
+	Reason         string     `json:"reason"`
+	CancelBookings bool       `json:"cancel_bookings"`
+}
+
+// AffectedBooking identifies a booking cancelled by an operator action and
+// the user who held it.
+type AffectedBooking struct {
+	BookingID  uuid.UUID  `json:"booking_id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	UserEmail  string     `json:"user_email"`
+	UserName   string     `json:"user_name"`
+	ResourceID uuid.UUID  `json:"resource_id"`
+	TimeSlotID *uuid.UUID `json:"time_slot_id,omitempty"`
+	StartTime  time.Time  `json:"start_time"`
+	EndTime    time.Time  `json:"end_time"`
+}
+
+type BlackoutResponse struct {
+	Blackout         Blackout          `json:"blackout"`
+	AffectedBookings []AffectedBooking `json:"affected_bookings"`
+}
+
+// WaitlistEntry is a user's place in the queue for a full time slot. Offered
+// entries hold a seat until OfferExpiresAt.
+type WaitlistEntry struct {
+	bun.BaseModel  `bun:"waitlist_entries"`
+	ID             uuid.UUID  `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
+	TimeSlotID     uuid.UUID  `json:"time_slot_id" db:"time_slot_id" bun:"time_slot_id,notnull"`
+	ResourceID     uuid.UUID  `json:"resource_id" db:"resource_id" bun:"resource_id,notnull"`
+	UserID         uuid.UUID  `json:"user_id" db:"user_id" bun:"user_id,notnull"`
+	Status         string     `json:"status" db:"status" bun:"status,notnull,default:'waiting'" validate:"oneof=waiting offered booked left expired"`
+	AutoBook       bool       `json:"auto_book" db:"auto_book" bun:"auto_book,notnull"`
+	BookingID      *uuid.UUID `json:"booking_id,omitempty" db:"booking_id" bun:"booking_id"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty" db:"offer_expires_at" bun:"offer_expires_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at" bun:"updated_at,notnull,default:now()"`
+}
+
+type JoinWaitlistRequest struct {
+	TimeSlotID uuid.UUID `json:"time_slot_id" validate:"required"`
+	// AutoBook books the freed seat immediately; otherwise the user gets an
+	// offer to claim it before it expires. Defaults to true.
+	AutoBook *bool `json:"auto_book"`
+}
+
+// WaitlistPosition is a waitlist entry with its slot times and, while
+// waiting, its 1-based place in the queue.
+type WaitlistPosition struct {
+	WaitlistEntry `bun:",extend"`
+	Position      int       `json:"position,omitempty" bun:"position"`
+	StartTime     time.Time `json:"start_time" bun:"start_time"`
+	EndTime       time.Time `json:"end_time" bun:"end_time"`
+}
+
+// ResourceProvider links a provider user to a resource whose bookings they
+// may approve.
+type ResourceProvider struct {
+	bun.BaseModel `bun:"resource_providers"`
+	ResourceID    uuid.UUID `json:"resource_id" db:"resource_id" bun:"resource_id,pk"`
+	UserID        uuid.UUID `json:"user_id" db:"user_id" bun:"user_id,pk"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+}
+
+type AddResourceProviderRequest struct {
+	UserID uuid.UUID `json:"user_id" validate:"required"`
+}
+
+// BookingTransition records a booking status change and who made it. A nil
+// ActorID means the system (expiry, sweepers).
+type BookingTransition struct {
+	bun.BaseModel `bun:"booking_transitions"`
+	ID            uuid.UUID  `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
+	BookingID     uuid.UUID  `json:"booking_id" db:"booking_id" bun:"booking_id,notnull"`
+	FromStatus    string     `json:"from_status,omitempty" db:"from_status" bun:"from_status,nullzero"`
+	ToStatus      string     `json:"to_status" db:"to_status" bun:"to_status,notnull"`
+	ActorID       *uuid.UUID `json:"actor_id,omitempty" db:"actor_id" bun:"actor_id"`
+	Reason        string     `json:"reason,omitempty" db:"reason" bun:"reason,nullzero"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+}
+
+type ReviewBookingRequest struct {
+	Reason string `json:"reason"`
+}
+
+// BookingSeries groups the bookings created from one recurrence rule.
+type BookingSeries struct {
+	bun.BaseModel `bun:"booking_series"`
+	ID            uuid.UUID `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
+	UserID        uuid.UUID `json:"user_id" db:"user_id" bun:"user_id,notnull"`
+	ResourceID    uuid.UUID `json:"resource_id" db:"resource_id" bun:"resource_id,notnull"`
+	Rule          string    `json:"rule" db:"rule" bun:"rule,notnull"`
+	Notes         string    `json:"notes" db:"notes" bun:"notes"`
+	Status        string    `json:"status" db:"status" bun:"status,notnull,default:'active'" validate:"oneof=active cancelled"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at" bun:"updated_at,notnull,default:now()"`
+}
+
+// CreateBookingSeriesRequest books TimeSlotID and the slots on the same
+// resource at the same local time on each later occurrence of Rule. With
+// AllOrNothing, nothing is booked unless every occurrence is available.
+// With PayWithCredits each occurrence takes a credit instead of a payment.
+type CreateBookingSeriesRequest struct {
+	ResourceID     uuid.UUID `json:"resource_id" validate:"required"`
+	TimeSlotID     uuid.UUID `json:"time_slot_id" validate:"required"`
+	Rule           string    `json:"rule" validate:"required"`
+	Notes          string    `json:"notes"`
+	AllOrNothing   bool      `json:"all_or_nothing"`
+	PayWithCredits bool      `json:"pay_with_credits"`
+}
+
+// SeriesOccurrence reports the outcome of one occurrence of a series.
+type SeriesOccurrence struct {
+	StartTime  time.Time  `json:"start_time"`
+	EndTime    time.Time  `json:"end_time"`
+	TimeSlotID *uuid.UUID `json:"time_slot_id,omitempty"`
+	BookingID  *uuid.UUID `json:"booking_id,omitempty"`
+	Status     string     `json:"status"` // booked, conflict, or available when an all-or-nothing series was not booked
+	Reason     string     `json:"reason,omitempty"`
+}
+
+type BookingSeriesResponse struct {
+	Series      *BookingSeries     `json:"series,omitempty"`
+	Occurrences []SeriesOccurrence `json:"occurrences,omitempty"`
+	Bookings    []Booking          `json:"bookings,omitempty"`
+}
+
+type CancelSeriesRequest struct {
+	// Scope is occurrence, following or all
+	Scope     string     `json:"scope" validate:"oneof=occurrence following all"`
+	BookingID *uuid.UUID `json:"booking_id"`
+}
+
+// ResourcePool groups interchangeable resources, such as identical courts,
+// so a booking can target the pool and be assigned a member by Strategy.
+type ResourcePool struct {
+	bun.BaseModel  `bun:"resource_pools"`
+	ID             uuid.UUID            `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
+	Name           string               `json:"name" db:"name" bun:"name,notnull"`
+	Description    string               `json:"description" db:"description" bun:"description"`
+	Strategy       string               `json:"strategy" db:"strategy" bun:"strategy,notnull,default:'least_used'" validate:"oneof=least_used round_robin priority"`
+	LastResourceID *uuid.UUID           `json:"last_resource_id,omitempty" db:"last_resource_id" bun:"last_resource_id"`
+	Members        []ResourcePoolMember `json:"members,omitempty" bun:"-"`
+	CreatedAt      time.Time            `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+	UpdatedAt      time.Time            `json:"updated_at" db:"updated_at" bun:"updated_at,notnull,default:now()"`
+}
+
+// ResourcePoolMember places a resource in a pool. Lower priorities are
+// assigned first by the priority strategy and break ties for the others.
+type ResourcePoolMember struct {
+	bun.BaseModel `bun:"resource_pool_members"`
+	PoolID        uuid.UUID `json:"pool_id" db:"pool_id" bun:"pool_id,pk"`
+	ResourceID    uuid.UUID `json:"resource_id" db:"resource_id" bun:"resource_id,pk"`
+	Priority      int       `json:"priority" db:"priority" bun:"priority,notnull,default:0"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+}
+
+type CreateResourcePoolRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+	Strategy    string `json:"strategy" validate:"omitempty,oneof=least_used round_robin priority"`
+}
+
+type AddPoolMemberRequest struct {
+	ResourceID uuid.UUID `json:"resource_id" validate:"required"`
+	Priority   int       `json:"priority"`
+}
+
+// BookingBundle groups bookings of several resources for the same interval,
+// such as a doctor and an exam room, that succeed or fail together.
+type BookingBundle struct {
+	bun.BaseModel `bun:"booking_bundles"`
+	ID            uuid.UUID `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
+	UserID        uuid.UUID `json:"user_id" db:"user_id" bun:"user_id,notnull"`
+	StartTime     time.Time `json:"start_time" db:"start_time" bun:"start_time,notnull"`
+	EndTime       time.Time `json:"end_time" db:"end_time" bun:"end_time,notnull"`
+	Notes         string    `json:"notes" db:"notes" bun:"notes"`
+	Status        string    `json:"status" db:"status" bun:"status,notnull,default:'active'" validate:"oneof=active cancelled"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at" bun:"updated_at,notnull,default:now()"`
+}
+
+// BundleItem is one component of a bundle: a specific resource or any free
+// member of a pool.
+type BundleItem struct {
+	ResourceID *uuid.UUID `json:"resource_id"`
+	PoolID     *uuid.UUID `json:"pool_id"`
+}
+
+type CreateBundleRequest struct {
+	Items     []BundleItem `json:"items" validate:"required,min=1"`
+	StartTime time.Time    `json:"start_time" validate:"required"`
+	EndTime   time.Time    `json:"end_time" validate:"required"`
+	Notes     string       `json:"notes"`
+	// PayWithCredits pays for every component with credits instead of money
+	PayWithCredits bool `json:"pay_with_credits"`
+}
+
+type BookingBundleResponse struct {
+	Bundle   *BookingBundle `json:"bundle"`
+	Bookings []Booking      `json:"bookings"`
+}
+
+// ResourceDependency requires bookings of a resource to include a member of
+// a pool, such as a doctor needing one of the exam rooms.
+type ResourceDependency struct {
+	bun.BaseModel `bun:"resource_dependencies"`
+	ResourceID    uuid.UUID `json:"resource_id" db:"resource_id" bun:"resource_id,pk"`
+	PoolID        uuid.UUID `json:"pool_id" db:"pool_id" bun:"pool_id,pk"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+}
+
+type AddResourceDependencyRequest struct {
+	PoolID uuid.UUID `json:"pool_id" validate:"required"`
+}
+
+// BookingRule limits when and how much users may book, for one resource,
+// every resource of a type, or all resources. Nil limits are off. Every
+// rule that applies to a resource is checked.
+type BookingRule struct {
+	bun.BaseModel     `bun:"booking_rules"`
+	ID                uuid.UUID  `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
+	Scope             string     `json:"scope" db:"scope" bun:"scope,notnull" validate:"oneof=global resource_type resource"`
+	ResourceType      string     `json:"resource_type,omitempty" db:"resource_type" bun:"resource_type,nullzero"`
+	ResourceID        *uuid.UUID `json:"resource_id,omitempty" db:"resource_id" bun:"resource_id"`
+	MinNoticeMinutes  *int       `json:"min_notice_minutes,omitempty" db:"min_notice_minutes" bun:"min_notice_minutes"`
+	MaxAdvanceDays    *int       `json:"max_advance_days,omitempty" db:"max_advance_days" bun:"max_advance_days"`
+	MaxActiveBookings *int       `json:"max_active_bookings,omitempty" db:"max_active_bookings" bun:"max_active_bookings"`
+	MaxHoursPerWeek   *float64   `json:"max_hours_per_week,omitempty" db:"max_hours_per_week" bun:"max_hours_per_week"`
+	OnePerDay         bool       `json:"one_per_day" db:"one_per_day" bun:"one_per_day,notnull,default:false"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at" bun:"updated_at,notnull,default:now()"`
+}
+
+type CreateBookingRuleRequest struct {
+	Scope             string     `json:"scope" validate:"omitempty,oneof=global resource_type resource"`
+	ResourceType      string     `json:"resource_type"`
+	ResourceID        *uuid.UUID `json:"resource_id"`
+	MinNoticeMinutes  *int       `json:"min_notice_minutes"`
+	MaxAdvanceDays    *int       `json:"max_advance_days"`
+	MaxActiveBookings *int       `json:"max_active_bookings"`
+	MaxHoursPerWeek   *float64   `json:"max_hours_per_week"`
+	OnePerDay         bool       `json:"one_per_day"`
+}
+
+// RuleViolation explains why a booking rule rejected a booking, in a form
+// the UI can show: a stable code, a message, and the limit that was hit.
+type RuleViolation struct {
+	RuleID  uuid.UUID `json:"rule_id"`
+	Code    string    `json:"code"`
+	Message string    `json:"message"`
+	Limit   float64   `json:"limit"`
+}
+
+// RuleViolationResponse is the body returned when booking rules reject a
+// booking.
+type RuleViolationResponse struct {
+	Error      string          `json:"error"`
+	Violations []RuleViolation `json:"violations"`
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in the minor units of an ISO 4217 currency: 2550 USD
+// is $25.50 and 2550 JPY is ¥2550. Amounts in different currencies are
+// never added or compared.
+//
+// In JSON it is {"amount": 2550, "currency": "USD"}. In Postgres it is the
+// money_amount composite type (amount BIGINT, currency CHAR(3)), so reports
+// can sum (total_amount).amount grouped by (total_amount).currency.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// currencyExponents lists the supported currencies with their number of
+// minor unit digits.
+var currencyExponents = map[string]int{
+	"AED": 2, "AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CLP": 0,
+	"CNY": 2, "CZK": 2, "DKK": 2, "EUR": 2, "GBP": 2, "HKD": 2, "HUF": 2,
+	"IDR": 2, "ILS": 2, "INR": 2, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0,
+	"KWD": 3, "LKR": 2, "MXN": 2, "MYR": 2, "NOK": 2, "NZD": 2, "OMR": 3,
+	"PHP": 2, "PLN": 2, "QAR": 2, "SAR": 2, "SEK": 2, "SGD": 2, "THB": 2,
+	"TND": 3, "TRY": 2, "TWD": 2, "USD": 2, "VND": 0, "ZAR": 2,
+}
+
+// ValidateCurrency checks that code is a supported ISO 4217 currency code.
+func ValidateCurrency(code string) error {
+	if _, ok := currencyExponents[code]; !ok {
+		return fmt.Errorf("unsupported currency %q: must be an ISO 4217 code such as USD, EUR or INR", code)
+	}
+	return nil
+}
+
+// NewMoney returns amount minor units of currency.
+func NewMoney(amount int64, currency string) *Money {
+	return &Money{Amount: amount, Currency: currency}
+}
+
+// Validate checks that the amount is not negative and its currency is
+// supported.
+func (m Money) Validate() error {
+	if m.Amount < 0 {
+		return fmt.Errorf("amount must not be negative")
+	}
+	return ValidateCurrency(m.Currency)
+}
+
+// In completes an amount given without a currency with currency and
+// rejects amounts in any other currency. A nil amount is left alone.
+func (m *Money) In(currency string) error {
+	if m == nil {
+		return nil
+	}
+	if m.Currency == "" {
+		m.Currency = currency
+	}
+	if m.Currency != currency {
+		return fmt.Errorf("amount is in %s but the resource is priced in %s", m.Currency, currency)
+	}
+	return m.Validate()
+}
+
+// Add returns m + o. It fails when the currencies differ.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("cannot add %s to %s", o.Currency, m.Currency)
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns m - o. It fails when the currencies differ.
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("cannot subtract %s from %s", o.Currency, m.Currency)
+	}
+	return Money{Amount: m.Amount - o.Amount, Currency: m.Currency}, nil
+}
+
+// Times returns m multiplied by n.
+func (m Money) Times(n int64) Money {
+	return Money{Amount: m.Amount * n, Currency: m.Currency}
+}
+
+// Scale returns m multiplied by factor, rounded half away from zero to
+// the nearest minor unit.
+func (m Money) Scale(factor float64) Money {
+	return Money{Amount: int64(math.Round(float64(m.Amount) * factor)), Currency: m.Currency}
+}
+
+// Percent returns percent percent of m, rounded to the nearest minor unit.
+func (m Money) Percent(percent float64) Money {
+	return m.Scale(percent / 100)
+}
+
+// Equal reports whether m and o are the same amount in the same currency.
+func (m Money) Equal(o Money) bool {
+	return m.Amount == o.Amount && m.Currency == o.Currency
+}
+
+// String formats the amount in major units, e.g. "25.50 USD".
+func (m Money) String() string {
+	exp := currencyExponents[m.Currency]
+	if exp == 0 {
+		return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+	}
+
+	sign, amount := "", m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	unit := int64(math.Pow10(exp))
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/unit, exp, amount%unit, m.Currency)
+}
+
+// Value stores the amount as a money_amount composite literal.
+func (m Money) Value() (driver.Value, error) {
+	return fmt.Sprintf("(%d,%s)", m.Amount, m.Currency), nil
+}
+
+// Scan reads a money_amount composite value such as "(2550,USD)".
+func (m *Money) Scan(src interface{}) error {
+	var raw string
+	switch v := src.(type) {
+	case []byte:
+		raw = string(v)
+	case string:
+		raw = v
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+
+	amount, currency, ok := strings.Cut(strings.Trim(raw, "()"), ",")
+	if !ok {
+		return fmt.Errorf("invalid money value %q", raw)
+	}
+
+	parsed, err := strconv.ParseInt(amount, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid money value %q: %w", raw, err)
+	}
+
+	m.Amount = parsed
+	m.Currency = strings.TrimSpace(strings.Trim(currency, `"`))
+	return nil
+}
@@ -0,0 +1,122 @@
+package models
+
+import "testing"
+
+func TestMoneyScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    Money
+		wantErr bool
+	}{
+		{name: "composite bytes", src: []byte("(2550,USD)"), want: Money{2550, "USD"}},
+		{name: "composite string", src: "(2550,USD)", want: Money{2550, "USD"}},
+		{name: "negative amount", src: "(-150,EUR)", want: Money{-150, "EUR"}},
+		{name: "zero decimal currency", src: "(2550,JPY)", want: Money{2550, "JPY"}},
+		{name: "quoted currency", src: `(100,"GBP")`, want: Money{100, "GBP"}},
+		{name: "padded currency", src: "(100,USD )", want: Money{100, "USD"}},
+		{name: "no currency", src: "(2550)", wantErr: true},
+		{name: "amount not a number", src: "(25.50,USD)", wantErr: true},
+		{name: "unsupported type", src: int64(2550), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := got.Scan(tt.src)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Scan(%v) = %+v, want an error", tt.src, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Scan(%v): %v", tt.src, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Scan(%v) = %+v, want %+v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyValue(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{Money{2550, "USD"}, "(2550,USD)"},
+		{Money{-150, "EUR"}, "(-150,EUR)"},
+		{Money{0, "JPY"}, "(0,JPY)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got, err := tt.money.Value()
+			if err != nil {
+				t.Fatalf("Value: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Value() = %v, want %s", got, tt.want)
+			}
+
+			var scanned Money
+			if err := scanned.Scan(got); err != nil {
+				t.Fatalf("Scan(%v): %v", got, err)
+			}
+			if !scanned.Equal(tt.money) {
+				t.Errorf("Scan(Value()) = %+v, want %+v", scanned, tt.money)
+			}
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{Money{2550, "USD"}, "25.50 USD"},
+		{Money{5, "USD"}, "0.05 USD"},
+		{Money{-5, "USD"}, "-0.05 USD"},
+		{Money{-2550, "EUR"}, "-25.50 EUR"},
+		{Money{2550, "JPY"}, "2550 JPY"},
+		{Money{-2550, "JPY"}, "-2550 JPY"},
+		{Money{1234, "KWD"}, "1.234 KWD"},
+		{Money{7, "KWD"}, "0.007 KWD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.money.String(); got != tt.want {
+				t.Errorf("String() of %+v = %q, want %q", tt.money, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyPercent(t *testing.T) {
+	tests := []struct {
+		name    string
+		money   Money
+		percent float64
+		want    Money
+	}{
+		{"whole cents", Money{2550, "USD"}, 10, Money{255, "USD"}},
+		{"half cent rounds up", Money{25, "USD"}, 50, Money{13, "USD"}},
+		{"negative half cent rounds away from zero", Money{-25, "USD"}, 50, Money{-13, "USD"}},
+		{"fraction below half rounds down", Money{1001, "USD"}, 10, Money{100, "USD"}},
+		{"fractional percent", Money{999, "USD"}, 33.3, Money{333, "USD"}},
+		{"full amount", Money{2550, "JPY"}, 100, Money{2550, "JPY"}},
+		{"zero percent", Money{2550, "USD"}, 0, Money{0, "USD"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.money.Percent(tt.percent); !got.Equal(tt.want) {
+				t.Errorf("%s.Percent(%v) = %s, want %s", tt.money, tt.percent, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Payment collects the price of a booking through a payment provider. The
+// booking stays pending until the payment succeeds and is expired if it
+// fails or is not made before ExpiresAt. ProviderIntentID is empty until
+// the intent is created with the provider, after the booking committed.
+type Payment struct {
+	bun.BaseModel    `bun:"payments"`
+	ID               uuid.UUID `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
+	BookingID        uuid.UUID `json:"booking_id" db:"booking_id" bun:"booking_id,notnull"`
+	Provider         string    `json:"provider" db:"provider" bun:"provider,notnull"`
+	ProviderIntentID string    `json:"provider_intent_id,omitempty" db:"provider_intent_id" bun:"provider_intent_id,nullzero"`
+	Amount           Money     `json:"amount" db:"amount" bun:"amount,notnull"`
+	Status           string    `json:"status" db:"status" bun:"status,notnull,default:'requires_payment'" validate:"oneof=requires_payment succeeded failed expired cancelled"`
+	// ClientSecret lets the booking's owner complete the payment with the
+	// provider; it is only returned to them
+	ClientSecret string     `json:"client_secret,omitempty" db:"client_secret" bun:"client_secret,nullzero"`
+	ExpiresAt    time.Time  `json:"expires_at" db:"expires_at" bun:"expires_at,notnull"`
+	PaidAt       *time.Time `json:"paid_at,omitempty" db:"paid_at" bun:"paid_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at" bun:"updated_at,notnull,default:now()"`
+}
+
+// PaymentEvent is a provider webhook event that has been handled.
+type PaymentEvent struct {
+	bun.BaseModel    `bun:"payment_events"`
+	Provider         string    `bun:"provider,pk"`
+	ID               string    `bun:"id,pk"`
+	Type             string    `bun:"type,notnull"`
+	ProviderIntentID string    `bun:"provider_intent_id,notnull"`
+	ReceivedAt       time.Time `bun:"received_at,notnull,default:now()"`
+}
+
+type SimulatePaymentRequest struct {
+	Outcome string `json:"outcome" validate:"required,oneof=succeeded failed"`
+}
+
+// Refund returns part or all of a booking's payment. Cancellation refunds
+// follow the resource's cancellation policy; manual and goodwill refunds
+// are issued by admins. A refund is recorded as pending with the change
+// that causes it and sent to the provider once that has committed;
+// ProviderRefundID is empty until then.
+type Refund struct {
+	bun.BaseModel    `bun:"refunds"`
+	ID               uuid.UUID  `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
+	BookingID        uuid.UUID  `json:"booking_id" db:"booking_id" bun:"booking_id,notnull"`
+	PaymentID        uuid.UUID  `json:"payment_id" db:"payment_id" bun:"payment_id,notnull"`
+	ProviderRefundID string     `json:"provider_refund_id,omitempty" db:"provider_refund_id" bun:"provider_refund_id,nullzero"`
+	Amount           Money      `json:"amount" db:"amount" bun:"amount,notnull"`
+	Kind             string     `json:"kind" db:"kind" bun:"kind,notnull" validate:"oneof=cancellation manual goodwill"`
+	Status           string     `json:"status" db:"status" bun:"status,notnull,default:'pending'" validate:"oneof=pending succeeded"`
+	Reason           string     `json:"reason,omitempty" db:"reason" bun:"reason,nullzero"`
+	ActorID          *uuid.UUID `json:"actor_id,omitempty" db:"actor_id" bun:"actor_id"`
+	// IdempotencyKey identifies the refund to the provider, so sending it
+	// again never pays it out twice
+	IdempotencyKey string    `json:"-" db:"idempotency_key" bun:"idempotency_key,notnull"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+}
+
+// IssueRefundRequest refunds Amount, or everything still refundable when
+// it is omitted, of a booking's payment.
+type IssueRefundRequest struct {
+	Amount *Money `json:"amount"`
+	Kind   string `json:"kind" validate:"required,oneof=manual goodwill"`
+	Reason string `json:"reason" validate:"required"`
+}
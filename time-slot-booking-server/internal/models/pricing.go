@@ -0,0 +1,214 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// PricingRule adjusts the price of bookings on one resource, every resource
+// of a type, or all resources. Percentage adjustments are summed and applied
+// to the subtotal: positive values are surcharges, negative ones discounts.
+//
+//   - peak, off_peak: bookings starting on Weekdays (every day when empty)
+//     between Open and Close, local time
+//   - weekend: bookings starting on a Saturday or Sunday
+//   - member: bookings by members
+//   - early_bird: bookings made at least HoursBefore before the start
+//   - last_minute: bookings made less than HoursBefore before the start
+//   - per_seat: charges the base price for every seat of parties of at
+//     least MinPartySize, instead of once per booking
+type PricingRule struct {
+	bun.BaseModel `bun:"pricing_rules"`
+	ID            uuid.UUID  `json:"id" db:"id" bun:",pk,default:gen_random_uuid()"`
+	Scope         string     `json:"scope" db:"scope" bun:"scope,notnull" validate:"oneof=global resource_type resource"`
+	ResourceType  string     `json:"resource_type,omitempty" db:"resource_type" bun:"resource_type,nullzero"`
+	ResourceID    *uuid.UUID `json:"resource_id,omitempty" db:"resource_id" bun:"resource_id"`
+	Name          string     `json:"name" db:"name" bun:"name,notnull"`
+	Kind          string     `json:"kind" db:"kind" bun:"kind,notnull" validate:"oneof=peak off_peak weekend member early_bird last_minute per_seat"`
+	Weekdays      []string   `json:"weekdays,omitempty" db:"weekdays" bun:"weekdays,array"`
+	Open          string     `json:"open,omitempty" db:"open" bun:"open,nullzero"`
+	Close         string     `json:"close,omitempty" db:"close" bun:"close,nullzero"`
+	HoursBefore   *float64   `json:"hours_before,omitempty" db:"hours_before" bun:"hours_before"`
+	MinPartySize  int        `json:"min_party_size,omitempty" db:"min_party_size" bun:"min_party_size,notnull,default:0"`
+	AdjustPercent float64    `json:"adjust_percent" db:"adjust_percent" bun:"adjust_percent,notnull,default:0"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at" bun:"created_at,notnull,default:now()"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at" bun:"updated_at,notnull,default:now()"`
+}
+
+type CreatePricingRuleRequest struct {
+	Scope         string     `json:"scope" validate:"omitempty,oneof=global resource_type resource"`
+	ResourceType  string     `json:"resource_type"`
+	ResourceID    *uuid.UUID `json:"resource_id"`
+	Name          string     `json:"name" validate:"required"`
+	Kind          string     `json:"kind" validate:"required,oneof=peak off_peak weekend member early_bird last_minute per_seat"`
+	Weekdays      []string   `json:"weekdays"`
+	Open          string     `json:"open"`
+	Close         string     `json:"close"`
+	HoursBefore   *float64   `json:"hours_before"`
+	MinPartySize  int        `json:"min_party_size"`
+	AdjustPercent float64    `json:"adjust_percent"`
+}
+
+// Validate checks that the rule has what its kind needs.
+func (r *PricingRule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if r.AdjustPercent < -100 {
+		return fmt.Errorf("adjust_percent cannot discount more than 100%%")
+	}
+
+	switch r.Kind {
+	case "peak", "off_peak":
+		if err := (TimeInterval{Open: r.Open, Close: r.Close}).Validate(); err != nil {
+			return err
+		}
+		for _, day := range r.Weekdays {
+			if !isWeekday(day) {
+				return fmt.Errorf("unknown weekday %q", day)
+			}
+		}
+	case "early_bird", "last_minute":
+		if r.HoursBefore == nil || *r.HoursBefore <= 0 {
+			return fmt.Errorf("%s rules need a positive hours_before", r.Kind)
+		}
+	case "per_seat":
+		if r.MinPartySize < 0 {
+			return fmt.Errorf("min_party_size must not be negative")
+		}
+	case "weekend", "member":
+	default:
+		return fmt.Errorf("kind must be peak, off_peak, weekend, member, early_bird, last_minute or per_seat")
+	}
+
+	return nil
+}
+
+// PriceRequest is what a price depends on.
+type PriceRequest struct {
+	BasePrice *Money
+	StartTime time.Time
+	Location  *time.Location
+	PartySize int
+	Member    bool
+	BookedAt  time.Time
+}
+
+// Applies reports whether the rule adjusts the price of the request.
+func (r *PricingRule) Applies(req *PriceRequest) bool {
+	local := req.StartTime.In(req.Location)
+
+	switch r.Kind {
+	case "peak", "off_peak":
+		if len(r.Weekdays) > 0 {
+			onDay := false
+			for _, day := range r.Weekdays {
+				onDay = onDay || day == Weekdays[local.Weekday()]
+			}
+			if !onDay {
+				return false
+			}
+		}
+		open, close, err := TimeInterval{Open: r.Open, Close: r.Close}.Bounds()
+		clock := wallClock(local)
+		return err == nil && clock >= open && clock < close
+	case "weekend":
+		return local.Weekday() == time.Saturday || local.Weekday() == time.Sunday
+	case "member":
+		return req.Member
+	case "early_bird":
+		return req.StartTime.Sub(req.BookedAt).Hours() >= *r.HoursBefore
+	case "last_minute":
+		return req.StartTime.Sub(req.BookedAt).Hours() < *r.HoursBefore
+	case "per_seat":
+		return req.PartySize >= r.MinPartySize
+	}
+
+	return false
+}
+
+// PriceLine is one step of a price calculation.
+type PriceLine struct {
+	RuleID      *uuid.UUID `json:"rule_id,omitempty"`
+	Kind        string     `json:"kind"`
+	Description string     `json:"description"`
+	Amount      Money      `json:"amount"`
+}
+
+// PriceQuote is the itemised price of a booking. BasePrice and Total are
+// nil for unpriced slots; otherwise every amount is in the currency of the
+// base price.
+type PriceQuote struct {
+	ResourceID uuid.UUID   `json:"resource_id"`
+	TimeSlotID *uuid.UUID  `json:"time_slot_id,omitempty"`
+	StartTime  time.Time   `json:"start_time"`
+	EndTime    time.Time   `json:"end_time"`
+	PartySize  int         `json:"party_size"`
+	BasePrice  *Money      `json:"base_price"`
+	Lines      []PriceLine `json:"lines"`
+	Total      *Money      `json:"total"`
+	QuotedAt   time.Time   `json:"quoted_at"`
+}
+
+// QuotePrice prices a request under the given rules. The base price is
+// charged once, or once per seat when a per_seat rule applies; the
+// percentage adjustments of all other matching rules are then added up and
+// applied to that subtotal, each rounded to the minor unit. The total never
+// goes below zero.
+func QuotePrice(rules []PricingRule, req *PriceRequest) *PriceQuote {
+	quote := &PriceQuote{
+		StartTime: req.StartTime,
+		PartySize: req.PartySize,
+		BasePrice: req.BasePrice,
+		Lines:     make([]PriceLine, 0),
+		QuotedAt:  req.BookedAt,
+	}
+
+	if req.BasePrice == nil {
+		return quote
+	}
+
+	subtotal := *req.BasePrice
+	quote.Lines = append(quote.Lines, PriceLine{Kind: "base", Description: "base price", Amount: subtotal})
+
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Kind != "per_seat" || !rule.Applies(req) || req.PartySize <= 1 {
+			continue
+		}
+		extra := subtotal.Times(int64(req.PartySize - 1))
+		quote.Lines = append(quote.Lines, PriceLine{
+			RuleID:      &rule.ID,
+			Kind:        rule.Kind,
+			Description: fmt.Sprintf("%s: %d more seats", rule.Name, req.PartySize-1),
+			Amount:      extra,
+		})
+		subtotal.Amount += extra.Amount
+		break
+	}
+
+	total := subtotal
+	for i := range rules {
+		rule := &rules[i]
+		if rule.AdjustPercent == 0 || !rule.Applies(req) {
+			continue
+		}
+		amount := subtotal.Percent(rule.AdjustPercent)
+		quote.Lines = append(quote.Lines, PriceLine{
+			RuleID:      &rule.ID,
+			Kind:        rule.Kind,
+			Description: fmt.Sprintf("%s (%+g%%)", rule.Name, rule.AdjustPercent),
+			Amount:      amount,
+		})
+		total.Amount += amount.Amount
+	}
+
+	if total.Amount < 0 {
+		total.Amount = 0
+	}
+	quote.Total = &total
+	return quote
+}
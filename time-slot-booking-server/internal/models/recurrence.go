@@ -0,0 +1,122 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxSeriesOccurrences caps how many bookings one recurring series creates.
+const MaxSeriesOccurrences = 104
+
+// RecurrenceRule is the subset of RFC 5545 RRULE supported for booking
+// series: FREQ=WEEKLY or FREQ=MONTHLY with an optional INTERVAL and exactly
+// one of COUNT or UNTIL. Biweekly is FREQ=WEEKLY;INTERVAL=2.
+type RecurrenceRule struct {
+	Freq     string
+	Interval int
+	Count    int
+	// Until is either a UTC instant (20060102T150405Z) or a date (20060102)
+	// that includes the whole local day.
+	Until     time.Time
+	UntilDate bool
+}
+
+// ParseRecurrenceRule parses an RRULE value such as
+// "FREQ=WEEKLY;INTERVAL=2;COUNT=10". A leading "RRULE:" is allowed.
+func ParseRecurrenceRule(rule string) (*RecurrenceRule, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return nil, fmt.Errorf("recurrence rule is required")
+	}
+
+	r := &RecurrenceRule{Interval: 1}
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(value)
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("INTERVAL must be a positive integer")
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("COUNT must be a positive integer")
+			}
+			r.Count = n
+		case "UNTIL":
+			if t, err := time.Parse("20060102T150405Z", value); err == nil {
+				r.Until = t
+			} else if t, err := time.Parse("20060102", value); err == nil {
+				r.Until = t
+				r.UntilDate = true
+			} else {
+				return nil, fmt.Errorf("UNTIL must be YYYYMMDD or YYYYMMDDTHHMMSSZ")
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rule part %q", key)
+		}
+	}
+
+	if r.Freq != "WEEKLY" && r.Freq != "MONTHLY" {
+		return nil, fmt.Errorf("FREQ must be WEEKLY or MONTHLY")
+	}
+	if (r.Count == 0) == r.Until.IsZero() {
+		return nil, fmt.Errorf("exactly one of COUNT or UNTIL is required")
+	}
+	if r.Count > MaxSeriesOccurrences {
+		return nil, fmt.Errorf("COUNT must not exceed %d", MaxSeriesOccurrences)
+	}
+
+	return r, nil
+}
+
+// Occurrences returns the start times of the series beginning at first,
+// stepping on the wall clock of loc so a standing 7pm booking stays at 7pm
+// across DST changes. Monthly dates that do not exist in a month, such as
+// the 31st, are skipped as RFC 5545 requires. The result never exceeds
+// MaxSeriesOccurrences.
+func (r *RecurrenceRule) Occurrences(first time.Time, loc *time.Location) []time.Time {
+	first = first.In(loc)
+
+	until := r.Until
+	if r.UntilDate {
+		y, m, d := r.Until.Date()
+		until = time.Date(y, m, d+1, 0, 0, 0, 0, loc).Add(-time.Nanosecond)
+	}
+
+	limit := MaxSeriesOccurrences
+	if r.Count > 0 {
+		limit = r.Count
+	}
+
+	var occurrences []time.Time
+	// Bound the walk so sparse monthly rules cannot loop forever
+	for i := 0; len(occurrences) < limit && i < 12*MaxSeriesOccurrences; i++ {
+		var next time.Time
+		if r.Freq == "WEEKLY" {
+			next = first.AddDate(0, 0, 7*r.Interval*i)
+		} else {
+			next = first.AddDate(0, r.Interval*i, 0)
+			if next.Day() != first.Day() {
+				continue
+			}
+		}
+
+		if !until.IsZero() && next.After(until) {
+			break
+		}
+		occurrences = append(occurrences, next)
+	}
+
+	return occurrences
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// New York springs forward on 2025-03-09 and falls back on 2025-11-02.
+func TestOccurrencesAcrossDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	utc := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2025, month, day, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		first time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "weekly across spring forward",
+			rule:  "FREQ=WEEKLY;COUNT=3",
+			first: time.Date(2025, 3, 2, 9, 0, 0, 0, newYork),
+			want:  []time.Time{utc(3, 2, 14, 0), utc(3, 9, 13, 0), utc(3, 16, 13, 0)},
+		},
+		{
+			name:  "weekly across fall back",
+			rule:  "FREQ=WEEKLY;COUNT=3",
+			first: time.Date(2025, 10, 26, 9, 0, 0, 0, newYork),
+			want:  []time.Time{utc(10, 26, 13, 0), utc(11, 2, 14, 0), utc(11, 9, 14, 0)},
+		},
+		{
+			name:  "biweekly across spring forward",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;COUNT=2",
+			first: time.Date(2025, 3, 1, 18, 30, 0, 0, newYork),
+			want:  []time.Time{utc(3, 1, 23, 30), utc(3, 15, 22, 30)},
+		},
+		{
+			name:  "monthly across spring forward",
+			rule:  "FREQ=MONTHLY;COUNT=2",
+			first: time.Date(2025, 2, 9, 9, 0, 0, 0, newYork),
+			want:  []time.Time{utc(2, 9, 14, 0), utc(3, 9, 13, 0)},
+		},
+		{
+			name:  "until date includes the local fall back day",
+			rule:  "FREQ=WEEKLY;UNTIL=20251102",
+			first: time.Date(2025, 10, 19, 23, 30, 0, 0, newYork),
+			want:  []time.Time{utc(10, 20, 3, 30), utc(10, 27, 3, 30), utc(11, 3, 4, 30)},
+		},
+		{
+			name:  "until instant excludes a later local time",
+			rule:  "FREQ=WEEKLY;UNTIL=20250309T130000Z",
+			first: time.Date(2025, 3, 2, 9, 30, 0, 0, newYork),
+			want:  []time.Time{utc(3, 2, 14, 30)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRecurrenceRule(%q): %v", tt.rule, err)
+			}
+
+			got := rule.Occurrences(tt.first, newYork)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d occurrences, want %d: %v", len(got), len(tt.want), got)
+			}
+
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d = %s, want %s", i, got[i].UTC(), tt.want[i])
+				}
+				if h, m, _ := got[i].Clock(); h != tt.first.Hour() || m != tt.first.Minute() {
+					t.Errorf("occurrence %d is at %02d:%02d local, want %s", i, h, m, tt.first.Format("15:04"))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Weekdays are the keys accepted in OperatingHours.Weekly.
+var Weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// TimeInterval is a wall-clock opening interval within a day, as "HH:MM".
+// Close may be "24:00" for intervals that run until midnight.
+type TimeInterval struct {
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
+
+// OperatingHours is the weekly schedule stored on a resource and used to
+// generate its time slots.
+//
+// Open and Close apply to every day unless Weekly has an entry for that day;
+// an empty list in Weekly marks the day as closed. Slots are only generated
+// when SlotMinutes is set.
+type OperatingHours struct {
+	Open            string                    `json:"open,omitempty"`
+	Close           string                    `json:"close,omitempty"`
+	Weekly          map[string][]TimeInterval `json:"weekly,omitempty"`
+	SlotMinutes     int                       `json:"slot_minutes,omitempty"`
+	BufferMinutes   int                       `json:"buffer_minutes,omitempty"`
+	DefaultPrice    *Money                    `json:"default_price,omitempty"`
+	DefaultCapacity int                       `json:"default_capacity,omitempty"`
+}
+
+// IntervalsFor returns the opening intervals for a weekday.
+func (h *OperatingHours) IntervalsFor(day time.Weekday) []TimeInterval {
+	if intervals, ok := h.Weekly[Weekdays[day]]; ok {
+		return intervals
+	}
+	if h.Open != "" && h.Close != "" {
+		return []TimeInterval{{Open: h.Open, Close: h.Close}}
+	}
+	return nil
+}
+
+// GeneratesSlots reports whether the schedule has enough information to
+// generate time slots.
+func (h *OperatingHours) GeneratesSlots() bool {
+	return h != nil && h.SlotMinutes > 0
+}
+
+func (h *OperatingHours) Validate() error {
+	if h.SlotMinutes < 0 {
+		return fmt.Errorf("slot_minutes must not be negative")
+	}
+	if h.BufferMinutes < 0 {
+		return fmt.Errorf("buffer_minutes must not be negative")
+	}
+	if h.DefaultCapacity < 0 {
+		return fmt.Errorf("default_capacity must not be negative")
+	}
+	if h.DefaultPrice != nil && h.DefaultPrice.Amount < 0 {
+		return fmt.Errorf("default_price must not be negative")
+	}
+	if (h.Open == "") != (h.Close == "") {
+		return fmt.Errorf("open and close must be set together")
+	}
+	if h.Open != "" {
+		if err := (TimeInterval{Open: h.Open, Close: h.Close}).Validate(); err != nil {
+			return err
+		}
+	}
+
+	for day, intervals := range h.Weekly {
+		if !isWeekday(day) {
+			return fmt.Errorf("unknown weekday %q", day)
+		}
+		for _, interval := range intervals {
+			if err := interval.Validate(); err != nil {
+				return fmt.Errorf("%s: %w", day, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Bounds returns the interval as offsets from midnight.
+func (i TimeInterval) Bounds() (open, close time.Duration, err error) {
+	open, err = ParseClock(i.Open)
+	if err != nil {
+		return 0, 0, err
+	}
+	close, err = ParseClock(i.Close)
+	if err != nil {
+		return 0, 0, err
+	}
+	return open, close, nil
+}
+
+func (i TimeInterval) Validate() error {
+	open, close, err := i.Bounds()
+	if err != nil {
+		return err
+	}
+	if close <= open {
+		return fmt.Errorf("interval %s-%s must close after it opens", i.Open, i.Close)
+	}
+	return nil
+}
+
+// IntervalRules constrain free-form bookings on resources in interval
+// booking mode. Zero values leave the corresponding limit off.
+type IntervalRules struct {
+	MinMinutes         int    `json:"min_minutes,omitempty"`
+	MaxMinutes         int    `json:"max_minutes,omitempty"`
+	GranularityMinutes int    `json:"granularity_minutes,omitempty"`
+	HourlyRate         *Money `json:"hourly_rate,omitempty"`
+}
+
+func (r *IntervalRules) Validate() error {
+	if r.MinMinutes < 0 || r.MaxMinutes < 0 || r.GranularityMinutes < 0 {
+		return fmt.Errorf("interval limits must not be negative")
+	}
+	if r.MaxMinutes > 0 && r.MaxMinutes < r.MinMinutes {
+		return fmt.Errorf("max_minutes must not be less than min_minutes")
+	}
+	if r.HourlyRate != nil && r.HourlyRate.Amount < 0 {
+		return fmt.Errorf("hourly_rate must not be negative")
+	}
+	return nil
+}
+
+// Check validates a requested booking interval against the rules and, when
+// hours is set, the resource's operating hours on the local day in loc. A
+// nil receiver applies no duration or granularity limits.
+func (r *IntervalRules) Check(start, end time.Time, hours *OperatingHours, loc *time.Location) error {
+	if !end.After(start) {
+		return fmt.Errorf("end_time must be after start_time")
+	}
+
+	length := end.Sub(start)
+	if r != nil {
+		if r.MinMinutes > 0 && length < time.Duration(r.MinMinutes)*time.Minute {
+			return fmt.Errorf("booking must be at least %d minutes", r.MinMinutes)
+		}
+		if r.MaxMinutes > 0 && length > time.Duration(r.MaxMinutes)*time.Minute {
+			return fmt.Errorf("booking must be at most %d minutes", r.MaxMinutes)
+		}
+		if r.GranularityMinutes > 0 {
+			step := time.Duration(r.GranularityMinutes) * time.Minute
+			if wallClock(start.In(loc))%step != 0 || wallClock(end.In(loc))%step != 0 {
+				return fmt.Errorf("start and end must be on a %d minute boundary", r.GranularityMinutes)
+			}
+		}
+	}
+
+	if hours == nil {
+		return nil
+	}
+
+	localStart, localEnd := start.In(loc), end.In(loc)
+	from, to := wallClock(localStart), wallClock(localEnd)
+
+	// An interval may run up to midnight but not into the next day
+	sy, sm, sd := localStart.Date()
+	if ey, em, ed := localEnd.Date(); ey != sy || em != sm || ed != sd {
+		if localEnd.Sub(time.Date(sy, sm, sd+1, 0, 0, 0, 0, loc)) != 0 {
+			return fmt.Errorf("booking must start and end on the same day")
+		}
+		to = 24 * time.Hour
+	}
+
+	for _, interval := range hours.IntervalsFor(localStart.Weekday()) {
+		open, close, err := interval.Bounds()
+		if err != nil {
+			continue
+		}
+		if from >= open && to <= close {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("booking is outside operating hours")
+}
+
+// Price returns the cost of a booking of the given length at the hourly
+// rate, or nil when the resource has no rate.
+func (r *IntervalRules) Price(length time.Duration) *Money {
+	if r == nil || r.HourlyRate == nil {
+		return nil
+	}
+	price := r.HourlyRate.Scale(length.Hours())
+	return &price
+}
+
+// wallClock returns the local time of day of t as an offset from midnight.
+func wallClock(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+}
+
+// ParseClock parses "HH:MM" into an offset from midnight. "24:00" is
+// accepted as the end of the day.
+func ParseClock(s string) (time.Duration, error) {
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// ValidateTimeZone checks that tz is a known IANA time zone name.
+func ValidateTimeZone(tz string) error {
+	if tz == "" || tz == "Local" {
+		return fmt.Errorf("time zone must be an IANA name such as Europe/Berlin")
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return fmt.Errorf("unknown time zone %q", tz)
+	}
+	return nil
+}
+
+func isWeekday(day string) bool {
+	for _, d := range Weekdays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// FakeSignatureHeader carries the hex HMAC-SHA256 of a fake webhook body.
+const FakeSignatureHeader = "Fake-Signature"
+
+// FakeProvider is an in-memory gateway. Intents wait until Simulate settles
+// them, which produces the webhook a real gateway would send. State is lost
+// on restart, so intents created before one cannot be captured or
+// refunded.
+type FakeProvider struct {
+	secret []byte
+
+	mu       sync.Mutex
+	intents  map[string]*fakeIntent
+	byKey    map[string]string
+	refunded map[string]*Refund
+}
+
+type fakeIntent struct {
+	Intent
+	refunded int64
+}
+
+func NewFakeProvider(webhookSecret string) *FakeProvider {
+	return &FakeProvider{
+		secret:   []byte(webhookSecret),
+		intents:  make(map[string]*fakeIntent),
+		byKey:    make(map[string]string),
+		refunded: make(map[string]*Refund),
+	}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if id, ok := p.byKey[req.IdempotencyKey]; ok && req.IdempotencyKey != "" {
+		intent := p.intents[id].Intent
+		return &intent, nil
+	}
+
+	id := "pi_fake_" + uuid.NewString()
+	p.intents[id] = &fakeIntent{Intent: Intent{
+		ID:           id,
+		Status:       IntentRequiresPayment,
+		Amount:       req.Amount,
+		ClientSecret: id + "_secret_" + uuid.NewString(),
+	}}
+	if req.IdempotencyKey != "" {
+		p.byKey[req.IdempotencyKey] = id
+	}
+
+	intent := p.intents[id].Intent
+	return &intent, nil
+}
+
+func (p *FakeProvider) Capture(ctx context.Context, intentID string) (*Intent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[intentID]
+	if !ok {
+		return nil, fmt.Errorf("payment intent %s not found", intentID)
+	}
+
+	switch intent.Status {
+	case IntentAuthorized:
+		intent.Status = IntentSucceeded
+	case IntentSucceeded:
+	default:
+		return nil, fmt.Errorf("payment intent %s is %s and cannot be captured", intentID, intent.Status)
+	}
+
+	captured := intent.Intent
+	return &captured, nil
+}
+
+func (p *FakeProvider) Refund(ctx context.Context, req RefundRequest) (*Refund, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if refund, ok := p.refunded[req.IdempotencyKey]; ok && req.IdempotencyKey != "" {
+		return refund, nil
+	}
+
+	intent, ok := p.intents[req.IntentID]
+	if !ok {
+		return nil, fmt.Errorf("payment intent %s not found", req.IntentID)
+	}
+	if intent.Status != IntentSucceeded {
+		return nil, fmt.Errorf("payment intent %s has not been captured", req.IntentID)
+	}
+	if req.Amount.Currency != intent.Amount.Currency {
+		return nil, fmt.Errorf("cannot refund %s of a payment in %s", req.Amount.Currency, intent.Amount.Currency)
+	}
+	if req.Amount.Amount <= 0 || intent.refunded+req.Amount.Amount > intent.Amount.Amount {
+		return nil, fmt.Errorf("refund of %s exceeds the refundable amount", req.Amount)
+	}
+
+	intent.refunded += req.Amount.Amount
+	refund := &Refund{
+		ID:     "re_fake_" + uuid.NewString(),
+		Status: IntentSucceeded,
+		Amount: req.Amount,
+	}
+	if req.IdempotencyKey != "" {
+		p.refunded[req.IdempotencyKey] = refund
+	}
+
+	return refund, nil
+}
+
+func (p *FakeProvider) VerifyWebhook(payload []byte, header http.Header) (*Event, error) {
+	signature, err := hex.DecodeString(header.Get(FakeSignatureHeader))
+	if err != nil || !hmac.Equal(signature, p.sign(payload)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	if event.ID == "" || event.IntentID == "" {
+		return nil, fmt.Errorf("invalid webhook payload: id and intent_id are required")
+	}
+
+	return &event, nil
+}
+
+// Simulate settles an intent as the customer would, authorizing it when
+// succeed is true and declining it otherwise, and returns the signed
+// webhook the gateway sends about it.
+func (p *FakeProvider) Simulate(intentID string, succeed bool) ([]byte, http.Header, error) {
+	p.mu.Lock()
+	intent, ok := p.intents[intentID]
+	if !ok {
+		p.mu.Unlock()
+		return nil, nil, fmt.Errorf("payment intent %s not found", intentID)
+	}
+	if intent.Status != IntentRequiresPayment {
+		p.mu.Unlock()
+		return nil, nil, fmt.Errorf("payment intent %s is already %s", intentID, intent.Status)
+	}
+
+	event := Event{ID: "evt_fake_" + uuid.NewString(), Type: EventAuthorized, IntentID: intentID}
+	intent.Status = IntentAuthorized
+	if !succeed {
+		event.Type = EventFailed
+		intent.Status = IntentFailed
+	}
+	p.mu.Unlock()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	header.Set(FakeSignatureHeader, hex.EncodeToString(p.sign(payload)))
+
+	return payload, header, nil
+}
+
+func (p *FakeProvider) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
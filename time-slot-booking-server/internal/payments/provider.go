@@ -0,0 +1,106 @@
+// Package payments connects bookings to a payment gateway. Gateways are
+// reached through the Provider interface; the built-in fake provider
+// settles payments on request and is meant for development and tests.
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"time-slot-booking-server/internal/models"
+)
+
+// Intent statuses
+const (
+	// IntentRequiresPayment waits for the customer to pay
+	IntentRequiresPayment = "requires_payment"
+	// IntentAuthorized has funds reserved that still have to be captured
+	IntentAuthorized = "authorized"
+	IntentSucceeded  = "succeeded"
+	IntentFailed     = "failed"
+)
+
+// Webhook event types
+const (
+	// EventAuthorized reports that the customer paid and the funds can be
+	// captured
+	EventAuthorized = "payment.authorized"
+	// EventSucceeded reports a payment captured by the gateway itself
+	EventSucceeded = "payment.succeeded"
+	// EventFailed reports a declined or abandoned payment
+	EventFailed = "payment.failed"
+)
+
+// ErrInvalidSignature is returned for webhooks that were not sent by the
+// provider.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Provider is a payment gateway. Implementations must be safe for
+// concurrent use; idempotency keys let callers retry CreateIntent and
+// Refund without charging or refunding twice.
+type Provider interface {
+	// Name identifies the provider in stored payments.
+	Name() string
+	// CreateIntent starts collecting amount from the customer.
+	CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error)
+	// Capture collects the funds of an authorized intent.
+	Capture(ctx context.Context, intentID string) (*Intent, error)
+	// Refund returns part or all of a captured intent.
+	Refund(ctx context.Context, req RefundRequest) (*Refund, error)
+	// VerifyWebhook authenticates a webhook request and decodes its event.
+	VerifyWebhook(payload []byte, header http.Header) (*Event, error)
+}
+
+// IntentRequest asks the provider to collect a payment.
+type IntentRequest struct {
+	Amount models.Money
+	// Reference ties the intent to a booking in the provider's dashboard
+	Reference      string
+	IdempotencyKey string
+}
+
+// Intent is a payment being collected by the provider.
+type Intent struct {
+	ID     string
+	Status string
+	Amount models.Money
+	// ClientSecret lets the client complete the payment with the provider
+	ClientSecret string
+}
+
+// RefundRequest asks the provider to return part of a captured payment.
+type RefundRequest struct {
+	IntentID       string
+	Amount         models.Money
+	IdempotencyKey string
+}
+
+// Refund is money returned to the customer.
+type Refund struct {
+	ID     string
+	Status string
+	Amount models.Money
+}
+
+// Event is a verified webhook notification about an intent. Providers may
+// deliver the same event more than once.
+type Event struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	IntentID string `json:"intent_id"`
+}
+
+// New returns the provider called name, or nil when name is "none" and
+// payments are disabled.
+func New(name, webhookSecret string) (Provider, error) {
+	switch name {
+	case "", "none":
+		return nil, nil
+	case "fake":
+		return NewFakeProvider(webhookSecret), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q: must be fake or none", name)
+	}
+}
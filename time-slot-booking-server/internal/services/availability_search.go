@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"time-slot-booking-server/internal/models"
+
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// Search finds free time slots across all resources matching the query,
+// earliest first, in a single query. A slot matches when it lies inside the
+// window, is at least MinMinutes long and is bookable for the whole party.
+// Resources in interval booking mode have no slots and are not searched.
+func (s *TimeSlotService) Search(ctx context.Context, q *models.SlotSearchQuery) ([]models.SlotSearchResult, int, error) {
+	partySize := q.PartySize
+	if partySize < 1 {
+		partySize = 1
+	}
+
+	results := make([]models.SlotSearchResult, 0)
+
+	query := s.db.NewSelect().
+		TableExpr("time_slots AS ts").
+		ColumnExpr("ts.*").
+		ColumnExpr("r.name AS resource_name, r.type AS resource_type, r.location, r.tags, r.time_zone").
+		ColumnExpr("ts.capacity - ts.booked_count AS seats_available").
+		ColumnExpr("COUNT(*) OVER () AS total_count").
+		Join("JOIN resources AS r ON r.id = ts.resource_id").
+		Where("ts.start_time >= ?", q.From).
+		Where("ts.end_time <= ?", q.To)
+
+	query = bookable(query, "ts", partySize)
+
+	if q.ResourceType != "" {
+		query = query.Where("r.type = ?", q.ResourceType)
+	}
+	if len(q.Tags) > 0 {
+		query = query.Where("r.tags @> ?", pgdialect.Array(q.Tags))
+	}
+	if q.Location != "" {
+		query = query.Where("r.location ILIKE ?", "%"+q.Location+"%")
+	}
+	if q.MinMinutes > 0 {
+		query = query.Where("ts.end_time - ts.start_time >= make_interval(mins => ?)", q.MinMinutes)
+	}
+
+	err := query.
+		OrderExpr("ts.start_time ASC, r.name ASC, ts.id ASC").
+		Limit(q.Limit).
+		Offset(q.Offset).
+		Scan(ctx, &results)
+
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search availability: %w", err)
+	}
+
+	// The window count rides along on every row; a page past the end has none
+	total := 0
+	if len(results) > 0 {
+		total = results[0].TotalCount
+	}
+
+	return results, total, nil
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"time-slot-booking-server/internal/db"
+	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/payments"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type BlackoutService struct {
+	db       *db.DB
+	bookings *BookingService
+}
+
+func NewBlackoutService(database *db.DB, provider payments.Provider) *BlackoutService {
+	return &BlackoutService{db: database, bookings: NewBookingService(database, provider)}
+}
+
+// Create stores a blackout period. When req.CancelBookings is set, active
+// bookings on affected time slots are cancelled in the same transaction and
+// reported back with the users who held them. Paid bookings are refunded in
+// full, since the customer did not cancel.
+func (s *BlackoutService) Create(ctx context.Context, req *models.CreateBlackoutRequest, createdBy uuid.UUID) (*models.BlackoutResponse, error) {
+	blackout := &models.Blackout{
+		Scope:        req.Scope,
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceID,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		Reason:       req.Reason,
+	}
+	if createdBy != uuid.Nil {
+		blackout.CreatedBy = &createdBy
+	}
+
+	if err := normalizeBlackoutScope(blackout); err != nil {
+		return nil, err
+	}
+
+	var cancelled []models.Booking
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewInsert().
+			Model(blackout).
+			Returning("*").
+			Exec(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to create blackout: %w", err)
+		}
+
+		if !req.CancelBookings {
+			return nil
+		}
+
+		cancelled, err = s.cancelBookingsInBlackout(ctx, tx, blackout)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range cancelled {
+		s.bookings.issueRefunds(ctx, &cancelled[i])
+	}
+
+	affected, err := s.describeAffected(ctx, cancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BlackoutResponse{
+		Blackout:         *blackout,
+		AffectedBookings: affected,
+	}, nil
+}
+
+// List returns blackouts overlapping [from, to). When resourceID is set only
+// blackouts that apply to that resource are returned.
+func (s *BlackoutService) List(ctx context.Context, resourceID *uuid.UUID, from, to time.Time) ([]models.Blackout, error) {
+	if resourceID != nil {
+		var resource models.Resource
+		err := s.db.NewSelect().
+			Model(&resource).
+			Where("id = ?", *resourceID).
+			Scan(ctx)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch resource: %w", err)
+		}
+
+		return resourceBlackouts(ctx, s.db, &resource, from, to)
+	}
+
+	blackouts := make([]models.Blackout, 0)
+	err := s.db.NewSelect().
+		Model(&blackouts).
+		Where("starts_at < ?", to).
+		Where("ends_at > ?", from).
+		Order("starts_at ASC").
+		Scan(ctx)
+
+	return blackouts, err
+}
+
+func (s *BlackoutService) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.NewDelete().
+		Model((*models.Blackout)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	return err
+}
+
+// describeAffected attaches decrypted user details to cancelled bookings.
+func (s *BlackoutService) describeAffected(ctx context.Context, bookings []models.Booking) ([]models.AffectedBooking, error) {
+	affected := make([]models.AffectedBooking, 0, len(bookings))
+	if len(bookings) == 0 {
+		return affected, nil
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(bookings))
+	for _, booking := range bookings {
+		userIDs = append(userIDs, booking.UserID)
+	}
+
+	var users []models.AppUser
+	err := s.db.NewSelect().
+		Model(&users).
+		Where("id IN (?)", bun.In(userIDs)).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch affected users: %w", err)
+	}
+
+	byID := make(map[uuid.UUID]*models.DecryptedAppUser, len(users))
+	for i := range users {
+		decrypted, err := decryptAppUser(&users[i])
+		if err != nil {
+			return nil, err
+		}
+		byID[decrypted.ID] = decrypted
+	}
+
+	for _, booking := range bookings {
+		entry := models.AffectedBooking{
+			BookingID:  booking.ID,
+			UserID:     booking.UserID,
+			ResourceID: booking.ResourceID,
+			TimeSlotID: booking.TimeSlotID,
+			StartTime:  booking.StartTime,
+			EndTime:    booking.EndTime,
+		}
+		if user, ok := byID[booking.UserID]; ok {
+			entry.UserEmail = user.Email
+			entry.UserName = user.Name
+		}
+		affected = append(affected, entry)
+	}
+
+	return affected, nil
+}
+
+// cancelBookingsInBlackout cancels the active bookings overlapping a
+// blackout, with the rest of the bundles they belong to, and refunds them in
+// full. It returns them; their refunds are sent once the blackout has
+// committed.
+func (s *BlackoutService) cancelBookingsInBlackout(ctx context.Context, tx bun.Tx, blackout *models.Blackout) ([]models.Booking, error) {
+	var bookings []models.Booking
+
+	query := tx.NewSelect().
+		TableExpr("bookings AS b").
+		ColumnExpr("b.*").
+		Join("JOIN resources AS r ON r.id = b.resource_id").
+		Where("b.status IN ('pending', 'confirmed')").
+		Where("b.start_time < ?", blackout.EndsAt).
+		Where("b.end_time > ?", blackout.StartsAt).
+		OrderExpr("b.start_time ASC").
+		For("UPDATE OF b")
+
+	switch blackout.Scope {
+	case "resource":
+		query = query.Where("r.id = ?", *blackout.ResourceID)
+	case "resource_type":
+		query = query.Where("r.type = ?", blackout.ResourceType)
+	}
+
+	if err := query.Scan(ctx, &bookings); err != nil {
+		return nil, fmt.Errorf("failed to find affected bookings: %w", err)
+	}
+
+	reason := "blackout"
+	if blackout.Reason != "" {
+		reason = "blackout: " + blackout.Reason
+	}
+
+	cancelled := make([]models.Booking, 0, len(bookings))
+	cancelledBundles := make(map[uuid.UUID]bool)
+	for i := range bookings {
+		booking := &bookings[i]
+
+		// A bundle only makes sense whole, so its other components are
+		// cancelled and refunded in full with it, and reported too
+		if booking.BundleID != nil {
+			if cancelledBundles[*booking.BundleID] {
+				continue
+			}
+
+			bundle, err := s.bookings.cancelBundle(ctx, tx, *booking.BundleID, blackout.CreatedBy, reason, false)
+			if err != nil {
+				return nil, err
+			}
+			cancelled = append(cancelled, bundle...)
+			cancelledBundles[*booking.BundleID] = true
+			continue
+		}
+
+		if err := s.bookings.cancelBooking(ctx, tx, booking, blackout.CreatedBy, reason); err != nil {
+			return nil, err
+		}
+
+		if err := s.bookings.refundDue(ctx, tx, booking, booking.TotalAmount, blackout.CreatedBy, reason); err != nil {
+			return nil, err
+		}
+		cancelled = append(cancelled, *booking)
+	}
+
+	return cancelled, nil
+}
+
+// normalizeBlackoutScope infers the scope from the target fields when it is
+// not given and checks that scope and target agree.
+func normalizeBlackoutScope(b *models.Blackout) error {
+	if b.Scope == "" {
+		switch {
+		case b.ResourceID != nil:
+			b.Scope = "resource"
+		case b.ResourceType != "":
+			b.Scope = "resource_type"
+		default:
+			b.Scope = "global"
+		}
+	}
+
+	if !b.EndsAt.After(b.StartsAt) {
+		return fmt.Errorf("ends_at must be after starts_at")
+	}
+
+	switch b.Scope {
+	case "global":
+		if b.ResourceID != nil || b.ResourceType != "" {
+			return fmt.Errorf("global blackouts cannot target a resource or resource type")
+		}
+	case "resource_type":
+		if b.ResourceType == "" || b.ResourceID != nil {
+			return fmt.Errorf("resource_type blackouts need resource_type and no resource_id")
+		}
+	case "resource":
+		if b.ResourceID == nil || b.ResourceType != "" {
+			return fmt.Errorf("resource blackouts need resource_id and no resource_type")
+		}
+	default:
+		return fmt.Errorf("invalid scope %q: must be global, resource_type or resource", b.Scope)
+	}
+
+	return nil
+}
+
+// resourceBlackouts returns the blackouts that apply to a resource and
+// overlap [from, to).
+func resourceBlackouts(ctx context.Context, idb bun.IDB, resource *models.Resource, from, to time.Time) ([]models.Blackout, error) {
+	blackouts := make([]models.Blackout, 0)
+	err := idb.NewSelect().
+		Model(&blackouts).
+		Where("starts_at < ?", to).
+		Where("ends_at > ?", from).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("scope = 'global'").
+				WhereOr("scope = 'resource_type' AND resource_type = ?", resource.Type).
+				WhereOr("scope = 'resource' AND resource_id = ?", resource.ID)
+		}).
+		Order("starts_at ASC").
+		Scan(ctx)
+
+	return blackouts, err
+}
+
+// notBlackedOut is a WHERE condition that excludes time slots, referenced by
+// the given table alias, overlapping a blackout that applies to them.
+func notBlackedOut(slotAlias string) string {
+	return fmt.Sprintf(`NOT EXISTS (
+		SELECT 1 FROM blackouts bo
+		WHERE bo.starts_at < %[1]s.end_time AND bo.ends_at > %[1]s.start_time
+		AND (bo.scope = 'global'
+			OR (bo.scope = 'resource' AND bo.resource_id = %[1]s.resource_id)
+			OR (bo.scope = 'resource_type' AND bo.resource_type = (
+				SELECT r.type FROM resources r WHERE r.id = %[1]s.resource_id)))
+	)`, slotAlias)
+}
+
+// slotBlackedOut reports whether a blackout period covers the time slot.
+func slotBlackedOut(ctx context.Context, idb bun.IDB, timeSlotID uuid.UUID) (bool, error) {
+	open, err := idb.NewSelect().
+		Model((*models.TimeSlot)(nil)).
+		Where("id = ?", timeSlotID).
+		Where(notBlackedOut("time_slot")).
+		Exists(ctx)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check blackout periods: %w", err)
+	}
+
+	return !open, nil
+}
+
+// intervalBlackedOut reports whether a blackout period overlaps a free-form
+// interval on the resource.
+func intervalBlackedOut(ctx context.Context, idb bun.IDB, resourceID uuid.UUID, start, end time.Time) (bool, error) {
+	blocked, err := idb.NewSelect().
+		Model((*models.Blackout)(nil)).
+		Where("starts_at < ?", end).
+		Where("ends_at > ?", start).
+		Where(`scope = 'global'
+			OR (scope = 'resource' AND resource_id = ?)
+			OR (scope = 'resource_type' AND resource_type = (SELECT r.type FROM resources r WHERE r.id = ?))`, resourceID, resourceID).
+		Exists(ctx)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check blackout periods: %w", err)
+	}
+
+	return blocked, nil
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"time-slot-booking-server/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// ErrNotReviewer is returned when a user who is neither an admin nor a
+// provider of the resource tries to approve or reject a booking.
+var ErrNotReviewer = errors.New("not allowed to review bookings for this resource")
+
+// awaitingApproval matches pending bookings that are approval requests
+// rather than checkout holds.
+const awaitingApproval = "status = 'pending' AND hold_expires_at IS NULL"
+
+// ListPendingApprovals returns approval requests the reviewer may act on:
+// all of them for admins, those on their own resources for providers.
+func (s *BookingService) ListPendingApprovals(ctx context.Context, reviewerID uuid.UUID, reviewerRole string) ([]models.Booking, error) {
+	bookings := make([]models.Booking, 0)
+
+	query := s.db.NewSelect().
+		Model(&bookings).
+		Where(awaitingApproval).
+		Order("created_at ASC")
+
+	switch reviewerRole {
+	case "admin":
+	case "provider":
+		query = query.Where("resource_id IN (SELECT resource_id FROM resource_providers WHERE user_id = ?)", reviewerID)
+	default:
+		return nil, ErrNotReviewer
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list pending approvals: %w", err)
+	}
+
+	return bookings, nil
+}
+
+// Approve confirms a pending approval request. The seat was taken when the
+// request was made.
+func (s *BookingService) Approve(ctx context.Context, bookingID, reviewerID uuid.UUID, reviewerRole, reason string) (*models.Booking, error) {
+	return s.review(ctx, bookingID, reviewerID, reviewerRole, "confirmed", reason)
+}
+
+// Reject declines a pending approval request and frees its seat for the
+// waitlist.
+func (s *BookingService) Reject(ctx context.Context, bookingID, reviewerID uuid.UUID, reviewerRole, reason string) (*models.Booking, error) {
+	return s.review(ctx, bookingID, reviewerID, reviewerRole, "rejected", reason)
+}
+
+func (s *BookingService) review(ctx context.Context, bookingID, reviewerID uuid.UUID, reviewerRole, status, reason string) (*models.Booking, error) {
+	var booking models.Booking
+	var bundle []models.Booking
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		err := tx.NewSelect().
+			Model(&booking).
+			Where("id = ?", bookingID).
+			For("UPDATE").
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("booking not found: %w", err)
+		}
+
+		allowed, err := canReview(ctx, tx, reviewerID, reviewerRole, booking.ResourceID)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return ErrNotReviewer
+		}
+
+		if booking.Status != "pending" || booking.HoldExpiresAt != nil {
+			return fmt.Errorf("booking is not awaiting approval")
+		}
+
+		// The sweeper may not have run yet; a request for a started slot has lapsed
+		if !booking.StartTime.After(time.Now()) {
+			return fmt.Errorf("approval request has expired")
+		}
+
+		if err := setBookingStatus(ctx, tx, &booking, status, &reviewerID, reason); err != nil {
+			return fmt.Errorf("failed to update booking: %w", err)
+		}
+
+		if status != "rejected" {
+			return nil
+		}
+
+		if err := s.releaseBookingSeat(ctx, tx, &booking); err != nil {
+			return err
+		}
+
+		// Requests paid for up front get their money or credits back
+		if err := s.refundDue(ctx, tx, &booking, booking.TotalAmount, &reviewerID, "request rejected"); err != nil {
+			return err
+		}
+
+		if err := returnCredits(ctx, tx, &booking, &reviewerID, "request rejected"); err != nil {
+			return err
+		}
+
+		// A bundle only makes sense whole
+		if booking.BundleID != nil {
+			bundle, err = s.cancelBundle(ctx, tx, *booking.BundleID, &reviewerID, "bundle component rejected", false)
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.issueRefunds(ctx, &booking)
+	for i := range bundle {
+		s.issueRefunds(ctx, &bundle[i])
+	}
+
+	return &booking, nil
+}
+
+// ExpirePendingApprovals lapses approval requests nobody acted on before
+// their time slot started and frees their seats. It returns the number of
+// expired requests.
+func (s *BookingService) ExpirePendingApprovals(ctx context.Context) (int, error) {
+	expired := 0
+	var refunded []*models.Booking
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var bookings []models.Booking
+		err := tx.NewSelect().
+			Model(&bookings).
+			Where(awaitingApproval).
+			Where("start_time <= NOW()").
+			For("UPDATE SKIP LOCKED").
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to find lapsed approval requests: %w", err)
+		}
+
+		cancelledBundles := make(map[uuid.UUID]bool)
+		for i := range bookings {
+			booking := &bookings[i]
+			// Already cancelled with a lapsed sibling in its bundle
+			if booking.BundleID != nil && cancelledBundles[*booking.BundleID] {
+				continue
+			}
+
+			if err := setBookingStatus(ctx, tx, booking, "expired", nil, "not reviewed before the slot started"); err != nil {
+				return fmt.Errorf("failed to expire booking %s: %w", booking.ID, err)
+			}
+
+			if err := s.releaseBookingSeat(ctx, tx, booking); err != nil {
+				return err
+			}
+
+			if err := s.refundDue(ctx, tx, booking, booking.TotalAmount, nil, "request not reviewed in time"); err != nil {
+				return err
+			}
+			refunded = append(refunded, booking)
+
+			if err := returnCredits(ctx, tx, booking, nil, "request not reviewed in time"); err != nil {
+				return err
+			}
+			expired++
+
+			if booking.BundleID != nil {
+				bundle, err := s.cancelBundle(ctx, tx, *booking.BundleID, nil, "bundle component expired", false)
+				if err != nil {
+					return err
+				}
+				for j := range bundle {
+					refunded = append(refunded, &bundle[j])
+				}
+				cancelledBundles[*booking.BundleID] = true
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return expired, err
+	}
+
+	s.issueRefunds(ctx, refunded...)
+	return expired, nil
+}
+
+// GetTransitions returns the status history of a booking, oldest first.
+// Users see their own bookings' history, providers that of bookings on
+// resources assigned to them, admins any.
+func (s *BookingService) GetTransitions(ctx context.Context, bookingID, userID uuid.UUID, role string) ([]models.BookingTransition, error) {
+	var booking models.Booking
+	err := s.db.NewSelect().
+		Model(&booking).
+		Column("id", "user_id", "resource_id").
+		Where("id = ?", bookingID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("booking not found: %w", err)
+	}
+
+	allowed, err := canView(ctx, s.db, &booking, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("booking not found")
+	}
+
+	transitions := make([]models.BookingTransition, 0)
+	err = s.db.NewSelect().
+		Model(&transitions).
+		Where("booking_id = ?", bookingID).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	return transitions, err
+}
+
+// canView reports whether the user may see a booking: its owner and those
+// who may review bookings on its resource.
+func canView(ctx context.Context, idb bun.IDB, booking *models.Booking, userID uuid.UUID, role string) (bool, error) {
+	if booking.UserID == userID {
+		return true, nil
+	}
+
+	return canReview(ctx, idb, userID, role, booking.ResourceID)
+}
+
+// canReview reports whether the user may approve or reject bookings on the
+// resource: admins always, providers when assigned to it.
+func canReview(ctx context.Context, idb bun.IDB, userID uuid.UUID, role string, resourceID uuid.UUID) (bool, error) {
+	switch role {
+	case "admin":
+		return true, nil
+	case "provider":
+	default:
+		return false, nil
+	}
+
+	assigned, err := idb.NewSelect().
+		Model((*models.ResourceProvider)(nil)).
+		Where("resource_id = ?", resourceID).
+		Where("user_id = ?", userID).
+		Exists(ctx)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check resource providers: %w", err)
+	}
+
+	return assigned, nil
+}
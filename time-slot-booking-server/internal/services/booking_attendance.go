@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"time-slot-booking-server/internal/config"
+	"time-slot-booking-server/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// ErrNoShowLimit is returned when a user with too many recent no-shows
+// tries to book.
+var ErrNoShowLimit = errors.New("booking is blocked after repeated no-shows")
+
+// checkInWindow is how long before the start a booking can be checked in.
+const checkInWindow = 30 * time.Minute
+
+// MarkAttendance records whether a confirmed booking was used. Admins and
+// the resource's providers may mark it; check-in opens shortly before the
+// start, no_show and completed once the booking has started. A mark can be
+// corrected by marking the booking again.
+func (s *BookingService) MarkAttendance(ctx context.Context, bookingID, markerID uuid.UUID, markerRole, attendance string) (*models.Booking, error) {
+	switch attendance {
+	case "checked_in", "no_show", "completed":
+	default:
+		return nil, fmt.Errorf("attendance must be checked_in, no_show or completed")
+	}
+
+	var booking models.Booking
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		err := tx.NewSelect().
+			Model(&booking).
+			Where("id = ?", bookingID).
+			For("UPDATE").
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("booking not found: %w", err)
+		}
+
+		allowed, err := canReview(ctx, tx, markerID, markerRole, booking.ResourceID)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return ErrNotReviewer
+		}
+
+		if booking.Status != "confirmed" {
+			return fmt.Errorf("only confirmed bookings have attendance")
+		}
+
+		if booking.Attendance == attendance {
+			return fmt.Errorf("booking is already marked %s", attendance)
+		}
+
+		now := time.Now()
+		if attendance == "checked_in" {
+			if now.Before(booking.StartTime.Add(-checkInWindow)) {
+				return fmt.Errorf("check-in opens %s before the start", checkInWindow)
+			}
+		} else if now.Before(booking.StartTime) {
+			return fmt.Errorf("booking has not started yet")
+		}
+
+		return setAttendance(ctx, tx, &booking, attendance, &markerID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &booking, nil
+}
+
+// MarkNoShows marks confirmed bookings nobody checked in to as no_show once
+// the grace period after their start has passed. It returns the number of
+// bookings marked.
+func (s *BookingService) MarkNoShows(ctx context.Context, grace time.Duration) (int, error) {
+	marked := 0
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var bookings []models.Booking
+		err := tx.NewSelect().
+			Model(&bookings).
+			Where("status = ?", "confirmed").
+			Where("attendance IS NULL").
+			Where("start_time <= ?", time.Now().Add(-grace)).
+			For("UPDATE SKIP LOCKED").
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to find unattended bookings: %w", err)
+		}
+
+		for i := range bookings {
+			if err := setAttendance(ctx, tx, &bookings[i], "no_show", nil); err != nil {
+				return err
+			}
+			marked++
+		}
+
+		return nil
+	})
+
+	return marked, err
+}
+
+// setAttendance stores the attendance of a booking and records it in the
+// booking's history. A nil markerID marks a change made by the system.
+func setAttendance(ctx context.Context, tx bun.Tx, booking *models.Booking, attendance string, markerID *uuid.UUID) error {
+	_, err := tx.NewUpdate().
+		Model(booking).
+		Set("attendance = ?", attendance).
+		Set("attendance_marked_at = NOW()").
+		Set("updated_at = NOW()").
+		Where("id = ?", booking.ID).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to mark attendance of booking %s: %w", booking.ID, err)
+	}
+
+	return recordTransition(ctx, tx, booking.ID, booking.Status, booking.Status, markerID, "attendance: "+attendance)
+}
+
+// NoShowSummary returns the user's no-shows within the configured window
+// and whether they block new bookings.
+func (s *BookingService) NoShowSummary(ctx context.Context, userID uuid.UUID) (*models.NoShowSummary, error) {
+	count, err := recentNoShows(ctx, s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := config.AppConfig.NoShowLimit
+	return &models.NoShowSummary{
+		Count:      count,
+		Limit:      limit,
+		WindowDays: int(config.AppConfig.NoShowWindow.Hours() / 24),
+		Restricted: limit > 0 && count >= limit,
+	}, nil
+}
+
+// requireGoodStanding rejects new bookings and reschedules from users who
+// reached the no-show limit within the configured window.
+func requireGoodStanding(ctx context.Context, idb bun.IDB, userID uuid.UUID) error {
+	limit := config.AppConfig.NoShowLimit
+	if limit <= 0 {
+		return nil
+	}
+
+	count, err := recentNoShows(ctx, idb, userID)
+	if err != nil {
+		return err
+	}
+
+	if count >= limit {
+		return ErrNoShowLimit
+	}
+
+	return nil
+}
+
+func recentNoShows(ctx context.Context, idb bun.IDB, userID uuid.UUID) (int, error) {
+	count, err := idb.NewSelect().
+		Model((*models.Booking)(nil)).
+		Where("user_id = ?", userID).
+		Where("attendance = ?", "no_show").
+		Where("start_time > ?", time.Now().Add(-config.AppConfig.NoShowWindow)).
+		Count(ctx)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count no-shows: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,387 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"time-slot-booking-server/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// maxDependencyRounds bounds how many times CreateBundle adds pool members
+// to satisfy dependencies that the added members bring in themselves.
+const maxDependencyRounds = 5
+
+// CreateBundle books every item for the same interval in one transaction:
+// either all components are booked or none are. Slot resources need a slot
+// from start to end, interval resources take the range as is, and pool
+// items get a member not already in the bundle. Resources that depend on a
+// pool get a member of it added when the items do not include one. Each
+// priced component is paid for on its own; one that is not paid in time
+// cancels the whole bundle.
+func (s *BookingService) CreateBundle(ctx context.Context, userID uuid.UUID, req *models.CreateBundleRequest) (*models.BookingBundleResponse, error) {
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("a bundle needs at least one item")
+	}
+	if !req.EndTime.After(req.StartTime) {
+		return nil, fmt.Errorf("end_time must be after start_time")
+	}
+
+	response := &models.BookingBundleResponse{Bookings: make([]models.Booking, 0, len(req.Items))}
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := requireGoodStanding(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		bundle := &models.BookingBundle{
+			UserID:    userID,
+			StartTime: req.StartTime,
+			EndTime:   req.EndTime,
+			Notes:     req.Notes,
+			Status:    "active",
+		}
+
+		_, err := tx.NewInsert().
+			Model(bundle).
+			Returning("*").
+			Exec(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to create bundle: %w", err)
+		}
+
+		var booked []uuid.UUID
+		add := func(booking *models.Booking) {
+			response.Bookings = append(response.Bookings, *booking)
+			booked = append(booked, booking.ResourceID)
+		}
+
+		for i, item := range req.Items {
+			booking, err := s.bookBundleItem(ctx, tx, userID, item, req, booked)
+			if err != nil {
+				return fmt.Errorf("item %d: %w", i+1, err)
+			}
+			add(booking)
+		}
+
+		for round := 0; ; round++ {
+			missing, err := missingDependencies(ctx, tx, booked)
+			if err != nil {
+				return err
+			}
+			if len(missing) == 0 {
+				break
+			}
+			if round == maxDependencyRounds {
+				return fmt.Errorf("resource dependencies could not be satisfied")
+			}
+
+			for _, poolID := range missing {
+				booking, err := s.bookFromPool(ctx, tx, userID, poolID, req.StartTime, req.EndTime, req.Notes, 1, booked, false)
+				if err != nil {
+					return fmt.Errorf("required pool %s: %w", poolID, err)
+				}
+				add(booking)
+			}
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.Booking)(nil)).
+			Set("bundle_id = ?", bundle.ID).
+			Where("id IN (?)", bun.In(bookingIDs(response.Bookings))).
+			Exec(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to link bookings to bundle: %w", err)
+		}
+		for i := range response.Bookings {
+			booking := &response.Bookings[i]
+			booking.BundleID = &bundle.ID
+
+			if req.PayWithCredits {
+				err = payWithCredits(ctx, tx, booking)
+			} else {
+				err = s.requirePayment(ctx, tx, booking)
+			}
+			if err != nil {
+				return fmt.Errorf("component %d: %w", i+1, err)
+			}
+		}
+
+		response.Bundle = bundle
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range response.Bookings {
+		s.openPayments(ctx, &response.Bookings[i])
+	}
+	return response, nil
+}
+
+// bookBundleItem books one bundle component, skipping resources in exclude
+// when the item is a pool.
+func (s *BookingService) bookBundleItem(ctx context.Context, tx bun.Tx, userID uuid.UUID, item models.BundleItem, req *models.CreateBundleRequest, exclude []uuid.UUID) (*models.Booking, error) {
+	switch {
+	case item.PoolID != nil && item.ResourceID == nil:
+		return s.bookFromPool(ctx, tx, userID, *item.PoolID, req.StartTime, req.EndTime, req.Notes, 1, exclude, false)
+	case item.ResourceID == nil || item.PoolID != nil:
+		return nil, fmt.Errorf("exactly one of resource_id or pool_id is required")
+	}
+
+	resourceID := *item.ResourceID
+	for _, id := range exclude {
+		if id == resourceID {
+			return nil, fmt.Errorf("resource is already in the bundle")
+		}
+	}
+
+	var bookingMode string
+	err := tx.NewSelect().
+		Model((*models.Resource)(nil)).
+		Column("booking_mode").
+		Where("id = ?", resourceID).
+		Scan(ctx, &bookingMode)
+
+	if err != nil {
+		return nil, fmt.Errorf("resource not found: %w", err)
+	}
+
+	if bookingMode == "interval" {
+		return bookInterval(ctx, tx, userID, resourceID, req.StartTime, req.EndTime, req.Notes, 1)
+	}
+
+	timeSlotID, err := findSlotAt(ctx, tx, resourceID, req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.bookSlot(ctx, tx, userID, resourceID, timeSlotID, req.Notes, 1, nil)
+}
+
+// findSlotAt returns the resource's time slot that runs exactly from start
+// to end.
+func findSlotAt(ctx context.Context, tx bun.Tx, resourceID uuid.UUID, start, end time.Time) (uuid.UUID, error) {
+	var timeSlotID uuid.UUID
+	err := tx.NewSelect().
+		Model((*models.TimeSlot)(nil)).
+		Column("id").
+		Where("resource_id = ?", resourceID).
+		Where("start_time = ?", start).
+		Where("end_time = ?", end).
+		Limit(1).
+		Scan(ctx, &timeSlotID)
+
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("no time slot at this time")
+	}
+
+	return timeSlotID, nil
+}
+
+// missingDependencies returns the pools that some booked resource depends on
+// but that no booked resource belongs to.
+func missingDependencies(ctx context.Context, tx bun.Tx, resourceIDs []uuid.UUID) ([]uuid.UUID, error) {
+	missing := make([]uuid.UUID, 0)
+
+	err := tx.NewSelect().
+		Model((*models.ResourceDependency)(nil)).
+		ColumnExpr("DISTINCT pool_id").
+		Where("resource_id IN (?)", bun.In(resourceIDs)).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM resource_pool_members m
+			WHERE m.pool_id = resource_dependency.pool_id AND m.resource_id IN (?))`, bun.In(resourceIDs)).
+		Scan(ctx, &missing)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to check resource dependencies: %w", err)
+	}
+
+	return missing, nil
+}
+
+// requireStandalone rejects single bookings of a resource that depends on a
+// pool; it has to be booked in a bundle.
+func requireStandalone(ctx context.Context, tx bun.Tx, resourceID uuid.UUID) error {
+	dependent, err := hasDependencies(ctx, tx, resourceID)
+	if err != nil {
+		return err
+	}
+
+	if dependent {
+		return fmt.Errorf("resource requires other resources; book it as a bundle")
+	}
+
+	return nil
+}
+
+// hasDependencies reports whether a resource depends on a pool.
+func hasDependencies(ctx context.Context, tx bun.Tx, resourceID uuid.UUID) (bool, error) {
+	dependent, err := tx.NewSelect().
+		Model((*models.ResourceDependency)(nil)).
+		Where("resource_id = ?", resourceID).
+		Exists(ctx)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check resource dependencies: %w", err)
+	}
+
+	return dependent, nil
+}
+
+// GetUserBundles returns the user's bundles, newest first.
+func (s *BookingService) GetUserBundles(ctx context.Context, userID uuid.UUID) ([]models.BookingBundle, error) {
+	bundles := make([]models.BookingBundle, 0)
+
+	err := s.db.NewSelect().
+		Model(&bundles).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Scan(ctx)
+
+	return bundles, err
+}
+
+// GetBundle returns one of the user's bundles with its bookings.
+func (s *BookingService) GetBundle(ctx context.Context, bundleID, userID uuid.UUID) (*models.BookingBundleResponse, error) {
+	var bundle models.BookingBundle
+	err := s.db.NewSelect().
+		Model(&bundle).
+		Where("id = ?", bundleID).
+		Where("user_id = ?", userID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bookings := make([]models.Booking, 0)
+	err = s.db.NewSelect().
+		Model(&bookings).
+		Where("bundle_id = ?", bundleID).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bundle bookings: %w", err)
+	}
+
+	return &models.BookingBundleResponse{Bundle: &bundle, Bookings: bookings}, nil
+}
+
+// CancelBundle cancels the user's bundle and every active component under
+// their resources' cancellation policies, releasing their seats. A bundle
+// cannot be cancelled once it has started. It returns the cancelled
+// bookings.
+func (s *BookingService) CancelBundle(ctx context.Context, bundleID, userID uuid.UUID) ([]models.Booking, error) {
+	var cancelled []models.Booking
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var bundle models.BookingBundle
+		err := tx.NewSelect().
+			Model(&bundle).
+			Where("id = ?", bundleID).
+			Where("user_id = ?", userID).
+			For("UPDATE").
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("bundle not found: %w", err)
+		}
+
+		if bundle.Status == "cancelled" {
+			return fmt.Errorf("bundle is already cancelled")
+		}
+
+		cancelled, err = s.cancelBundle(ctx, tx, bundleID, &userID, "bundle cancelled", true)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range cancelled {
+		s.issueRefunds(ctx, &cancelled[i])
+	}
+
+	return cancelled, nil
+}
+
+// cancelBundle cancels the active components of a bundle and marks it
+// cancelled. Components that already ended some other way are left alone.
+// With policy, as when the user cancels, every component is cancelled on
+// the terms of its resource's cancellation policy, or none is when one of
+// them cannot be; otherwise, as when a component is rejected or lapses,
+// paid components are refunded in full.
+func (s *BookingService) cancelBundle(ctx context.Context, tx bun.Tx, bundleID uuid.UUID, actorID *uuid.UUID, reason string, policy bool) ([]models.Booking, error) {
+	cancelled := make([]models.Booking, 0)
+	err := tx.NewSelect().
+		Model(&cancelled).
+		Where("bundle_id = ?", bundleID).
+		Where("status IN ('pending', 'confirmed')").
+		For("UPDATE").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bundle bookings: %w", err)
+	}
+
+	if policy {
+		quotes := make([]*models.CancellationQuote, len(cancelled))
+		for i := range cancelled {
+			quote, err := quoteCancellation(ctx, tx, &cancelled[i], false)
+			if err != nil {
+				return nil, err
+			}
+			if !quote.Allowed {
+				return nil, fmt.Errorf("cannot cancel: %s", quote.Reason)
+			}
+			quotes[i] = quote
+		}
+
+		for i := range cancelled {
+			if err := s.cancelOnTerms(ctx, tx, &cancelled[i], quotes[i], actorID, reason); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		for i := range cancelled {
+			if err := s.cancelBooking(ctx, tx, &cancelled[i], actorID, reason); err != nil {
+				return nil, err
+			}
+
+			if err := s.refundDue(ctx, tx, &cancelled[i], cancelled[i].TotalAmount, actorID, reason); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	_, err = tx.NewUpdate().
+		Model((*models.BookingBundle)(nil)).
+		Set("status = ?", "cancelled").
+		Set("updated_at = NOW()").
+		Where("id = ?", bundleID).
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel bundle: %w", err)
+	}
+
+	return cancelled, nil
+}
+
+func bookingIDs(bookings []models.Booking) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(bookings))
+	for _, booking := range bookings {
+		ids = append(ids, booking.ID)
+	}
+	return ids
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"time-slot-booking-server/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// ErrCancelOverride is returned when someone other than an admin asks to
+// waive a cancellation policy.
+var ErrCancelOverride = errors.New("only admins can override the cancellation policy")
+
+// ErrFeeNotAccepted is returned when the cancellation fee is higher than the
+// one the user accepted.
+var ErrFeeNotAccepted = errors.New("cancellation fee is higher than the accepted fee")
+
+// PreviewCancel prices cancelling the booking now under its resource's
+// cancellation policy without cancelling it.
+func (s *BookingService) PreviewCancel(ctx context.Context, bookingID, userID uuid.UUID, role string, override bool) (*models.CancellationQuote, error) {
+	if override && role != "admin" {
+		return nil, ErrCancelOverride
+	}
+
+	var booking models.Booking
+	query := s.db.NewSelect().
+		Model(&booking).
+		Where("id = ?", bookingID)
+
+	if role != "admin" {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("booking not found: %w", err)
+	}
+
+	return quoteCancellation(ctx, s.db, &booking, override)
+}
+
+// Cancel cancels an active booking under its resource's cancellation
+// policy, storing the fee, the refund due and the policy applied on the
+// booking. The refund due on a paid booking is issued through the payment
+// provider once the cancellation has committed. Users cancel their own
+// bookings; admins may cancel any booking and waive the policy with
+// req.Override.
+func (s *BookingService) Cancel(ctx context.Context, bookingID, userID uuid.UUID, role string, req *models.CancelBookingRequest) (*models.Booking, error) {
+	if req.Override && role != "admin" {
+		return nil, ErrCancelOverride
+	}
+
+	var booking models.Booking
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		query := tx.NewSelect().
+			Model(&booking).
+			Where("id = ?", bookingID).
+			For("UPDATE")
+
+		if role != "admin" {
+			query = query.Where("user_id = ?", userID)
+		}
+
+		if err := query.Scan(ctx); err != nil {
+			return fmt.Errorf("booking not found: %w", err)
+		}
+
+		if booking.BundleID != nil {
+			return fmt.Errorf("booking is part of a bundle; cancel the bundle instead")
+		}
+
+		quote, err := quoteCancellation(ctx, tx, &booking, req.Override)
+		if err != nil {
+			return err
+		}
+
+		if !quote.Allowed {
+			return fmt.Errorf("cannot cancel: %s", quote.Reason)
+		}
+
+		if req.AcceptFee != nil && quote.Fee != nil {
+			if err := req.AcceptFee.In(quote.Fee.Currency); err != nil {
+				return fmt.Errorf("invalid accept_fee: %w", err)
+			}
+			if quote.Fee.Amount > req.AcceptFee.Amount {
+				return ErrFeeNotAccepted
+			}
+		}
+
+		reason := req.Reason
+		if req.Override {
+			reason = "cancellation policy waived by admin"
+			if req.Reason != "" {
+				reason += ": " + req.Reason
+			}
+		}
+
+		return s.cancelOnTerms(ctx, tx, &booking, quote, &userID, reason)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.issueRefunds(ctx, &booking)
+	return &booking, nil
+}
+
+// cancelOnTerms cancels an active booking the caller has locked on the terms
+// of its cancellation quote. The fee, the refund due and the policy applied
+// are stored on the booking, the credits of a late cancellation are kept
+// and the refund due on a paid booking is issued.
+func (s *BookingService) cancelOnTerms(ctx context.Context, tx bun.Tx, booking *models.Booking, quote *models.CancellationQuote, actorID *uuid.UUID, reason string) error {
+	booking.CancellationFee = quote.Fee
+	booking.RefundAmount = quote.Refund
+	booking.CancellationPolicy = quote.Policy
+
+	_, err := tx.NewUpdate().
+		Model(booking).
+		Column("cancellation_fee", "refund_amount", "cancellation_policy").
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to record cancellation terms: %w", err)
+	}
+
+	// Credits of a late cancellation are used up like the session
+	if booking.CreditsUsed > 0 && quote.CreditsReturned == 0 {
+		if err := forfeitCredits(ctx, tx, booking, actorID, "late cancellation"); err != nil {
+			return err
+		}
+	}
+
+	if err := s.cancelBooking(ctx, tx, booking, actorID, reason); err != nil {
+		return err
+	}
+
+	return s.refundDue(ctx, tx, booking, quote.Refund, actorID, reason)
+}
+
+// quoteCancellation prices cancelling an active booking now under the
+// policy of its resource.
+func quoteCancellation(ctx context.Context, idb bun.IDB, booking *models.Booking, override bool) (*models.CancellationQuote, error) {
+	if booking.Status != "pending" && booking.Status != "confirmed" {
+		return nil, fmt.Errorf("booking is already %s", booking.Status)
+	}
+
+	var resource models.Resource
+	err := idb.NewSelect().
+		Model(&resource).
+		Column("id", "cancellation_policy").
+		Where("id = ?", booking.ResourceID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cancellation policy: %w", err)
+	}
+
+	// Nothing has been paid on a booking still awaiting payment, so there
+	// is no fee to keep and nothing to refund; bookings paid with credits
+	// get them all back, or none once a fee would apply
+	amount := booking.TotalAmount
+	if booking.PaymentStatus == awaitingPayment || booking.CreditsUsed > 0 {
+		amount = nil
+	}
+
+	quote := models.QuoteCancellation(resource.CancellationPolicy, amount, booking.StartTime, time.Now(), override)
+	quote.BookingID = booking.ID
+	if booking.CreditsUsed > 0 && quote.Allowed && quote.FeePercent == 0 {
+		quote.CreditsReturned = booking.CreditsUsed
+	}
+
+	return quote, nil
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"time-slot-booking-server/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/uptrace/bun"
+)
+
+// ErrBookingConflict is returned when a requested range overlaps an active
+// booking on the resource.
+var ErrBookingConflict = errors.New("time range conflicts with existing bookings")
+
+// exclusionViolation is the Postgres error code raised when an insert breaks
+// the bookings_interval_no_overlap exclusion constraint.
+const exclusionViolation = "23P01"
+
+// CreateInterval books a free-form range on a resource in interval booking
+// mode. The range must satisfy the resource's interval rules and operating
+// hours. Overlaps are rejected by the bookings_interval_no_overlap
+// constraint, so concurrent requests cannot double-book the resource.
+func (s *BookingService) CreateInterval(ctx context.Context, userID uuid.UUID, req *models.CreateBookingRequest) (*models.Booking, error) {
+	partySize, err := partySizeOf(req.PartySize, req.Attendees)
+	if err != nil {
+		return nil, err
+	}
+
+	var booking *models.Booking
+
+	err = s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := requireGoodStanding(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		if err := requireStandalone(ctx, tx, req.ResourceID); err != nil {
+			return err
+		}
+
+		var err error
+		booking, err = bookInterval(ctx, tx, userID, req.ResourceID, *req.StartTime, *req.EndTime, req.Notes, partySize)
+		if err != nil {
+			return err
+		}
+
+		if err := checkQuotedTotal(booking, req.QuotedTotal); err != nil {
+			return err
+		}
+
+		if err := addAttendees(ctx, tx, booking, req.Attendees); err != nil {
+			return err
+		}
+
+		if req.PayWithCredits {
+			return payWithCredits(ctx, tx, booking)
+		}
+
+		return s.requirePayment(ctx, tx, booking)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.openPayments(ctx, booking)
+	return booking, nil
+}
+
+// bookInterval checks a free-form range against the resource's rules and
+// books it within the caller's transaction. The range holds the whole
+// resource, so the party only has to fit the resource's capacity.
+func bookInterval(ctx context.Context, tx bun.Tx, userID, resourceID uuid.UUID, start, end time.Time, notes string, partySize int) (*models.Booking, error) {
+	var resource models.Resource
+	err := tx.NewSelect().
+		Model(&resource).
+		Where("id = ?", resourceID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("resource not found: %w", err)
+	}
+
+	if resource.BookingMode != "interval" {
+		return nil, fmt.Errorf("resource takes time slot bookings; time_slot_id is required")
+	}
+
+	if err := resource.IntervalRules.Check(start, end, resource.OperatingHours, resource.TimeLocation()); err != nil {
+		return nil, err
+	}
+
+	if !start.After(time.Now()) {
+		return nil, fmt.Errorf("start_time must be in the future")
+	}
+
+	if partySize > resource.Capacity {
+		return nil, fmt.Errorf("party of %d exceeds the resource capacity of %d", partySize, resource.Capacity)
+	}
+
+	blackedOut, err := intervalBlackedOut(ctx, tx, resourceID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if blackedOut {
+		return nil, fmt.Errorf("time range is closed by a blackout period")
+	}
+
+	if err := checkBookingRules(ctx, tx, userID, resourceID, start, end, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	// Slot bookings are outside the exclusion constraint, so check all
+	// active bookings for a clear error before relying on it
+	if err := checkConflicts(ctx, tx, resourceID, start, end); err != nil {
+		return nil, err
+	}
+
+	quote, err := priceBooking(ctx, tx, userID, resourceID, start, end, resource.IntervalRules.Price(end.Sub(start)), partySize)
+	if err != nil {
+		return nil, err
+	}
+
+	booking := &models.Booking{
+		UserID:         userID,
+		ResourceID:     resourceID,
+		StartTime:      start,
+		EndTime:        end,
+		Status:         "confirmed",
+		Notes:          notes,
+		PartySize:      partySize,
+		TotalAmount:    quote.Total,
+		PriceBreakdown: quote.Lines,
+	}
+
+	if resource.RequiresApproval {
+		booking.Status = "pending"
+	}
+
+	_, err = tx.NewInsert().
+		Model(booking).
+		Returning("*").
+		Exec(ctx)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == exclusionViolation {
+		return nil, ErrBookingConflict
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create booking: %w", err)
+	}
+
+	if err := recordTransition(ctx, tx, booking.ID, "", booking.Status, &userID, ""); err != nil {
+		return nil, err
+	}
+
+	return booking, nil
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"time-slot-booking-server/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// partySizeOf returns the seats a booking request takes: one when unset.
+// Named attendees must fit in the party.
+func partySizeOf(partySize int, attendees []models.AttendeeRequest) (int, error) {
+	if partySize < 0 {
+		return 0, fmt.Errorf("party_size must be at least 1")
+	}
+	if partySize == 0 {
+		partySize = 1
+	}
+
+	if len(attendees) > partySize {
+		return 0, fmt.Errorf("%d attendees do not fit a party of %d", len(attendees), partySize)
+	}
+	for i, attendee := range attendees {
+		if attendee.Name == "" {
+			return 0, fmt.Errorf("attendee %d: name is required", i+1)
+		}
+	}
+
+	return partySize, nil
+}
+
+// addAttendees records the named members of a booking's party.
+func addAttendees(ctx context.Context, tx bun.Tx, booking *models.Booking, attendees []models.AttendeeRequest) error {
+	if len(attendees) == 0 {
+		return nil
+	}
+
+	rows := make([]models.BookingAttendee, 0, len(attendees))
+	for _, attendee := range attendees {
+		rows = append(rows, models.BookingAttendee{
+			BookingID: booking.ID,
+			Name:      attendee.Name,
+			Email:     attendee.Email,
+		})
+	}
+
+	_, err := tx.NewInsert().
+		Model(&rows).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to add attendees: %w", err)
+	}
+
+	booking.Attendees = rows
+	return nil
+}
+
+// loadAttendees fills in the named members of a booking's party.
+func loadAttendees(ctx context.Context, idb bun.IDB, booking *models.Booking) error {
+	booking.Attendees = make([]models.BookingAttendee, 0)
+
+	err := idb.NewSelect().
+		Model(&booking.Attendees).
+		Where("booking_id = ?", booking.ID).
+		Order("created_at ASC", "id ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to fetch attendees: %w", err)
+	}
+
+	return nil
+}
+
+// ReduceParty shrinks an active booking's party to partySize without
+// cancelling it. The freed seats go back to the time slot and on to its
+// waitlist, and a priced booking is requoted for the smaller party. Paid
+// bookings get the difference refunded and credits for the freed seats
+// back; bookings still awaiting payment have to be paid first.
+// Attendees, when given, replace the current list; otherwise the current
+// attendees must still fit the smaller party.
+func (s *BookingService) ReduceParty(ctx context.Context, bookingID, userID uuid.UUID, req *models.UpdatePartyRequest) (*models.Booking, error) {
+	var booking models.Booking
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		err := tx.NewSelect().
+			Model(&booking).
+			Where("id = ?", bookingID).
+			Where("user_id = ?", userID).
+			For("UPDATE").
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("booking not found: %w", err)
+		}
+
+		if booking.Status != "pending" && booking.Status != "confirmed" {
+			return fmt.Errorf("cannot change the party of a %s booking", booking.Status)
+		}
+
+		if req.PartySize < 1 || req.PartySize >= booking.PartySize {
+			return fmt.Errorf("party_size must be between 1 and %d", booking.PartySize-1)
+		}
+
+		// The open payment is for the whole party
+		if booking.PaymentStatus == awaitingPayment {
+			return fmt.Errorf("booking is awaiting payment and its party cannot be changed")
+		}
+
+		if req.Attendees != nil {
+			if _, err := partySizeOf(req.PartySize, req.Attendees); err != nil {
+				return err
+			}
+
+			_, err = tx.NewDelete().
+				Model((*models.BookingAttendee)(nil)).
+				Where("booking_id = ?", booking.ID).
+				Exec(ctx)
+
+			if err != nil {
+				return fmt.Errorf("failed to replace attendees: %w", err)
+			}
+
+			if err := addAttendees(ctx, tx, &booking, req.Attendees); err != nil {
+				return err
+			}
+		} else {
+			if err := loadAttendees(ctx, tx, &booking); err != nil {
+				return err
+			}
+			if len(booking.Attendees) > req.PartySize {
+				return fmt.Errorf("booking has %d attendees; send the attendees to keep", len(booking.Attendees))
+			}
+		}
+
+		freed := booking.PartySize - req.PartySize
+		paid := booking.TotalAmount
+
+		var timeSlot *models.TimeSlot
+		if booking.TimeSlotID != nil {
+			if timeSlot, err = lockTimeSlot(ctx, tx, *booking.TimeSlotID); err != nil {
+				return fmt.Errorf("time slot not found: %w", err)
+			}
+		}
+
+		quote, err := priceSmallerParty(ctx, tx, &booking, timeSlot, req.PartySize)
+		if err != nil {
+			return err
+		}
+
+		update := tx.NewUpdate().
+			Model(&booking).
+			Set("party_size = ?", req.PartySize).
+			Set("updated_at = NOW()").
+			Where("id = ?", booking.ID).
+			Returning("*")
+
+		if quote != nil {
+			update = update.
+				Set("total_amount = ?", quote.Total).
+				Set("price_breakdown = ?", quote.Lines)
+		}
+
+		if _, err := update.Exec(ctx); err != nil {
+			return fmt.Errorf("failed to update party size: %w", err)
+		}
+
+		reason := fmt.Sprintf("party reduced by %d to %d", freed, req.PartySize)
+		if err := recordTransition(ctx, tx, booking.ID, booking.Status, booking.Status, &userID, reason); err != nil {
+			return err
+		}
+
+		if err := returnPartyCredits(ctx, tx, &booking, req.PartySize, &userID, reason); err != nil {
+			return err
+		}
+
+		if quote != nil {
+			difference := *paid
+			difference.Amount -= quote.Total.Amount
+
+			// The party only ever shrinks, so each size is refunded once
+			key := fmt.Sprintf("party-%s-%d", booking.ID, req.PartySize)
+			if err := s.refundUpTo(ctx, tx, &booking, &difference, &userID, reason, key); err != nil {
+				return err
+			}
+		}
+
+		// Interval bookings hold the whole resource, not seats
+		if timeSlot == nil {
+			return nil
+		}
+
+		if err := releaseSeats(ctx, tx, *booking.TimeSlotID, freed); err != nil {
+			return err
+		}
+
+		return s.promoteWaitlist(ctx, tx, *booking.TimeSlotID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.issueRefunds(ctx, &booking)
+	return &booking, nil
+}
+
+// priceSmallerParty quotes a priced booking the caller has locked for a
+// party of partySize under the pricing rules that apply now, with the base
+// price of its time slot, or of its range for interval bookings. A smaller
+// party never costs more than the booking does, so it returns nil when the
+// quote is not lower.
+func priceSmallerParty(ctx context.Context, tx bun.Tx, booking *models.Booking, timeSlot *models.TimeSlot, partySize int) (*models.PriceQuote, error) {
+	if booking.TotalAmount == nil {
+		return nil, nil
+	}
+
+	var base *models.Money
+	if timeSlot != nil {
+		base = timeSlot.Price
+	} else {
+		var resource models.Resource
+		err := tx.NewSelect().
+			Model(&resource).
+			Where("id = ?", booking.ResourceID).
+			Scan(ctx)
+
+		if err != nil {
+			return nil, fmt.Errorf("resource not found: %w", err)
+		}
+		base = resource.IntervalRules.Price(booking.EndTime.Sub(booking.StartTime))
+	}
+
+	quote, err := priceBooking(ctx, tx, booking.UserID, booking.ResourceID, booking.StartTime, booking.EndTime, base, partySize)
+	if err != nil {
+		return nil, err
+	}
+
+	if quote.Total == nil || quote.Total.Currency != booking.TotalAmount.Currency || quote.Total.Amount >= booking.TotalAmount.Amount {
+		return nil, nil
+	}
+
+	return quote, nil
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"time-slot-booking-server/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// ErrPoolFull is returned when no member of a pool has a bookable slot at
+// the requested time.
+var ErrPoolFull = errors.New("no resource in the pool is available at this time")
+
+// BookPool books the slot from req.StartTime to req.EndTime on a member of
+// req.PoolID chosen by the pool's strategy, among those with seats for the
+// whole party. The pool row stays locked until the booking commits, so
+// concurrent pool bookings are assigned one at a time and each sees the
+// choices made before it.
+func (s *BookingService) BookPool(ctx context.Context, userID uuid.UUID, req *models.CreateBookingRequest) (*models.Booking, error) {
+	partySize, err := partySizeOf(req.PartySize, req.Attendees)
+	if err != nil {
+		return nil, err
+	}
+
+	var booking *models.Booking
+
+	err = s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := requireGoodStanding(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		var err error
+		booking, err = s.bookFromPool(ctx, tx, userID, *req.PoolID, *req.StartTime, *req.EndTime, req.Notes, partySize, nil, true)
+		if err != nil {
+			return err
+		}
+
+		if err := checkQuotedTotal(booking, req.QuotedTotal); err != nil {
+			return err
+		}
+
+		if err := addAttendees(ctx, tx, booking, req.Attendees); err != nil {
+			return err
+		}
+
+		if req.PayWithCredits {
+			return payWithCredits(ctx, tx, booking)
+		}
+
+		return s.requirePayment(ctx, tx, booking)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.openPayments(ctx, booking)
+	return booking, nil
+}
+
+// bookFromPool assigns and books seats on a pool member within the caller's
+// transaction, skipping the resources in exclude and, for a booking made on
+// its own rather than in a bundle, members that depend on other resources.
+func (s *BookingService) bookFromPool(ctx context.Context, tx bun.Tx, userID, poolID uuid.UUID, start, end time.Time, notes string, seats int, exclude []uuid.UUID, standalone bool) (*models.Booking, error) {
+	var pool models.ResourcePool
+	err := tx.NewSelect().
+		Model(&pool).
+		Where("id = ?", poolID).
+		For("UPDATE").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("pool not found: %w", err)
+	}
+
+	candidates, err := poolCandidates(ctx, tx, &pool, start, end, seats, exclude, standalone)
+	if err != nil {
+		return nil, err
+	}
+
+	var booking *models.Booking
+	var ruleErr *BookingRuleError
+	for i := range candidates {
+		slot := &candidates[i]
+
+		// A savepoint per attempt, so a slot that filled up since the
+		// candidate query only undoes that attempt
+		err := tx.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			booked, err := s.bookSlot(ctx, tx, userID, slot.ResourceID, slot.ID, notes, seats, nil)
+			if err != nil {
+				return err
+			}
+			booking = booked
+			return nil
+		})
+		if err == nil {
+			break
+		}
+
+		// Rules may differ per resource; keep the first violation in case
+		// no other member will take the booking
+		if ruleErr == nil {
+			errors.As(err, &ruleErr)
+		}
+	}
+
+	if booking == nil {
+		if ruleErr != nil {
+			return nil, ruleErr
+		}
+		return nil, ErrPoolFull
+	}
+
+	_, err = tx.NewUpdate().
+		Model((*models.Booking)(nil)).
+		Set("pool_id = ?", pool.ID).
+		Where("id = ?", booking.ID).
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to link booking to pool: %w", err)
+	}
+	booking.PoolID = &pool.ID
+
+	_, err = tx.NewUpdate().
+		Model((*models.ResourcePool)(nil)).
+		Set("last_resource_id = ?", booking.ResourceID).
+		Set("updated_at = NOW()").
+		Where("id = ?", pool.ID).
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update pool: %w", err)
+	}
+
+	return booking, nil
+}
+
+// poolCandidates returns the slots of pool members from start to end with
+// the given number of free seats, other than those of excluded resources
+// and, when standalone, of members with dependencies, in the order the
+// pool's strategy would assign them:
+//
+//   - least_used: fewest upcoming active bookings first
+//   - round_robin: members after the one assigned last first, wrapping around
+//   - priority: lowest member priority first
+//
+// Member priority, then resource ID, breaks ties.
+func poolCandidates(ctx context.Context, tx bun.Tx, pool *models.ResourcePool, start, end time.Time, seats int, exclude []uuid.UUID, standalone bool) ([]models.TimeSlot, error) {
+	var slots []models.TimeSlot
+
+	query := tx.NewSelect().
+		Model(&slots).
+		Join("JOIN resource_pool_members AS m ON m.resource_id = time_slot.resource_id").
+		Where("m.pool_id = ?", pool.ID).
+		Where("time_slot.start_time = ?", start).
+		Where("time_slot.end_time = ?", end)
+
+	if len(exclude) > 0 {
+		query = query.Where("time_slot.resource_id NOT IN (?)", bun.In(exclude))
+	}
+
+	// Members that must be booked with other resources only go in bundles
+	if standalone {
+		query = query.Where("NOT EXISTS (SELECT 1 FROM resource_dependencies AS d WHERE d.resource_id = time_slot.resource_id)")
+	}
+
+	query = bookable(query, "time_slot", seats)
+
+	switch pool.Strategy {
+	case "least_used":
+		query = query.OrderExpr(`(SELECT COUNT(*) FROM bookings b
+			WHERE b.resource_id = time_slot.resource_id
+			AND b.status IN ('pending', 'confirmed') AND b.start_time >= NOW()) ASC`)
+	case "round_robin":
+		if pool.LastResourceID != nil {
+			// false sorts first: members after the last assigned one lead
+			query = query.OrderExpr(`(m.priority, m.resource_id) <= (
+				SELECT lm.priority, lm.resource_id FROM resource_pool_members lm
+				WHERE lm.pool_id = m.pool_id AND lm.resource_id = ?) ASC`, *pool.LastResourceID)
+		}
+	}
+
+	err := query.
+		OrderExpr("m.priority ASC, time_slot.resource_id ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pool slots: %w", err)
+	}
+
+	return slots, nil
+}
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"time-slot-booking-server/internal/logger"
+	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/payments"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// ErrNothingToRefund is returned when a booking has no captured payment,
+// or its payment has already been refunded in full.
+var ErrNothingToRefund = errors.New("booking has no payment left to refund")
+
+// ErrRefundTooLarge is returned when a refund would return more than what
+// is left of the payment.
+var ErrRefundTooLarge = errors.New("refund exceeds the amount left to refund")
+
+// IssueRefund returns money on a paid booking outside the cancellation
+// policy, as a manual correction or a goodwill gesture, whatever the
+// booking's status. Admins only; the refund and its reason are recorded in
+// the booking's history. The caller's idempotency key identifies the
+// refund, so a retried request returns the refund already recorded under it
+// instead of paying out again.
+func (s *BookingService) IssueRefund(ctx context.Context, bookingID, adminID uuid.UUID, idempotencyKey string, req *models.IssueRefundRequest) (*models.Refund, error) {
+	if idempotencyKey == "" {
+		return nil, fmt.Errorf("idempotency key is required")
+	}
+	if req.Kind != "manual" && req.Kind != "goodwill" {
+		return nil, fmt.Errorf("kind must be manual or goodwill")
+	}
+	if req.Reason == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	var booking models.Booking
+	key := fmt.Sprintf("refund-%s-%s", bookingID, idempotencyKey)
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		err := tx.NewSelect().
+			Model(&booking).
+			Where("id = ?", bookingID).
+			For("UPDATE").
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("booking not found: %w", err)
+		}
+
+		var issued models.Refund
+		err = tx.NewSelect().
+			Model(&issued).
+			Where("idempotency_key = ?", key).
+			Scan(ctx)
+
+		if err == nil {
+			booking.Refund = &issued
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to fetch refund: %w", err)
+		}
+
+		payment, remaining, err := lockRefundable(ctx, tx, booking.ID)
+		if err != nil {
+			return err
+		}
+
+		amount := remaining
+		if req.Amount != nil {
+			amount = *req.Amount
+			if err := amount.In(remaining.Currency); err != nil {
+				return fmt.Errorf("invalid amount: %w", err)
+			}
+			if amount.Amount == 0 {
+				return fmt.Errorf("amount must be positive")
+			}
+			if amount.Amount > remaining.Amount {
+				return fmt.Errorf("%w: %s left", ErrRefundTooLarge, remaining)
+			}
+		}
+
+		return s.refund(ctx, tx, &booking, payment, amount, req.Kind, &adminID, req.Reason, key)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.issueRefunds(ctx, &booking)
+	return booking.Refund, nil
+}
+
+// GetRefunds lists the refunds of a booking, oldest first. Users see their
+// own bookings' refunds, admins any.
+func (s *BookingService) GetRefunds(ctx context.Context, bookingID, userID uuid.UUID, role string) ([]models.Refund, error) {
+	query := s.db.NewSelect().
+		Model((*models.Booking)(nil)).
+		Where("id = ?", bookingID)
+
+	if role != "admin" {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	exists, err := query.Exists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch booking: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("booking not found")
+	}
+
+	refunds := make([]models.Refund, 0)
+	err = s.db.NewSelect().
+		Model(&refunds).
+		Where("booking_id = ?", bookingID).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	return refunds, err
+}
+
+// refundDue returns up to amount of what was paid for a booking the caller
+// has locked when it is cancelled, rejected or expired. Unpaid bookings,
+// bookings refunded in full already and zero amounts are left alone; a
+// refund larger than what is left of the payment is cut down to it. With
+// payments disabled the refund is only recorded as due on the booking.
+func (s *BookingService) refundDue(ctx context.Context, tx bun.Tx, booking *models.Booking, amount *models.Money, actorID *uuid.UUID, reason string) error {
+	// A booking is cancelled once, so a retry after a failed transaction
+	// reuses the key and is not paid out twice
+	return s.refundUpTo(ctx, tx, booking, amount, actorID, reason, "cancellation-"+booking.ID.String())
+}
+
+// refundUpTo is refundDue with the caller's idempotency key.
+func (s *BookingService) refundUpTo(ctx context.Context, tx bun.Tx, booking *models.Booking, amount *models.Money, actorID *uuid.UUID, reason, idempotencyKey string) error {
+	if s.payments == nil || booking.PaymentStatus != "paid" || amount == nil || amount.Amount <= 0 {
+		return nil
+	}
+
+	payment, remaining, err := lockRefundable(ctx, tx, booking.ID)
+	if errors.Is(err, ErrNothingToRefund) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	due := *amount
+	if due.Currency != remaining.Currency {
+		return fmt.Errorf("refund in %s of a payment in %s", due.Currency, remaining.Currency)
+	}
+	if due.Amount > remaining.Amount {
+		due = remaining
+	}
+
+	return s.refund(ctx, tx, booking, payment, due, "cancellation", actorID, reason, idempotencyKey)
+}
+
+// refund records a pending refund of the payment, and a transition noting
+// it, against a booking the caller has locked and attaches it to the
+// booking. The provider is only asked for the refund once the transaction
+// has committed, by issueRefunds or the refund sweep, so a rolled back
+// cancellation pays nothing out.
+func (s *BookingService) refund(ctx context.Context, tx bun.Tx, booking *models.Booking, payment *models.Payment, amount models.Money, kind string, actorID *uuid.UUID, reason, idempotencyKey string) error {
+	if s.payments == nil {
+		return ErrPaymentsDisabled
+	}
+	if payment.Provider != s.payments.Name() {
+		return fmt.Errorf("payment was made through %s, which is no longer configured", payment.Provider)
+	}
+
+	refund := &models.Refund{
+		BookingID:      booking.ID,
+		PaymentID:      payment.ID,
+		Amount:         amount,
+		Kind:           kind,
+		Status:         "pending",
+		Reason:         reason,
+		ActorID:        actorID,
+		IdempotencyKey: idempotencyKey,
+	}
+
+	_, err := tx.NewInsert().
+		Model(refund).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to record refund: %w", err)
+	}
+	booking.Refund = refund
+
+	note := fmt.Sprintf("%s refund of %s", kind, amount)
+	if reason != "" {
+		note += ": " + reason
+	}
+
+	return recordTransition(ctx, tx, booking.ID, booking.Status, booking.Status, actorID, note)
+}
+
+// issueRefunds sends the pending refunds of bookings whose transaction has
+// just committed to the provider, so the money goes back right away. A
+// refund that cannot be sent now is left to the refund sweep.
+func (s *BookingService) issueRefunds(ctx context.Context, bookings ...*models.Booking) {
+	if s.payments == nil {
+		return
+	}
+
+	for _, booking := range bookings {
+		if booking.Refund == nil || booking.Refund.Status != "pending" {
+			continue
+		}
+
+		if err := issueRefund(ctx, s.db, s.payments, booking.Refund); err != nil {
+			logger.Warn().
+				Err(err).
+				Str("booking_id", booking.ID.String()).
+				Str("refund_id", booking.Refund.ID.String()).
+				Msg("Left refund for the refund sweep")
+		}
+	}
+}
+
+// IssuePendingRefunds sends the refunds recorded as pending to the
+// provider, when it failed or the transaction that recorded them did not
+// send them. A refund the provider rejects is logged and left pending, so
+// it does not hold up the others. It returns the number of refunds issued.
+func (s *BookingService) IssuePendingRefunds(ctx context.Context) (int, error) {
+	if s.payments == nil {
+		return 0, nil
+	}
+
+	var pending []models.Refund
+	err := s.db.NewSelect().
+		Model(&pending).
+		Join("JOIN payments AS p ON p.id = refund.payment_id").
+		Where("p.provider = ?", s.payments.Name()).
+		Where("refund.status = ?", "pending").
+		Order("refund.created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to find pending refunds: %w", err)
+	}
+
+	issued := 0
+	for i := range pending {
+		if err := issueRefund(ctx, s.db, s.payments, &pending[i]); err != nil {
+			logger.Warn().
+				Err(err).
+				Str("booking_id", pending[i].BookingID.String()).
+				Str("refund_id", pending[i].ID.String()).
+				Msg("Failed to issue pending refund")
+			continue
+		}
+		issued++
+	}
+
+	return issued, nil
+}
+
+// issueRefund asks the provider for a recorded refund and marks it
+// succeeded. The refund's idempotency key goes with it, so a retry, or a
+// sweep racing the request that recorded it, is not paid out twice.
+func issueRefund(ctx context.Context, idb bun.IDB, provider payments.Provider, refund *models.Refund) error {
+	var intentID string
+	err := idb.NewSelect().
+		Model((*models.Payment)(nil)).
+		Column("provider_intent_id").
+		Where("id = ?", refund.PaymentID).
+		Scan(ctx, &intentID)
+
+	if err != nil {
+		return fmt.Errorf("failed to fetch payment: %w", err)
+	}
+
+	issued, err := provider.Refund(ctx, payments.RefundRequest{
+		IntentID:       intentID,
+		Amount:         refund.Amount,
+		IdempotencyKey: refund.IdempotencyKey,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to refund payment: %w", err)
+	}
+
+	_, err = idb.NewUpdate().
+		Model(refund).
+		Set("provider_refund_id = ?", issued.ID).
+		Set("status = ?", "succeeded").
+		WherePK().
+		Where("status = ?", "pending").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to record refund: %w", err)
+	}
+	refund.ProviderRefundID = issued.ID
+	refund.Status = "succeeded"
+
+	return nil
+}
+
+// lockRefundable locks the captured payment of a booking and returns it
+// with the part of it not refunded yet.
+func lockRefundable(ctx context.Context, tx bun.Tx, bookingID uuid.UUID) (*models.Payment, models.Money, error) {
+	var payment models.Payment
+	err := tx.NewSelect().
+		Model(&payment).
+		Where("booking_id = ?", bookingID).
+		Where("status = ?", payments.IntentSucceeded).
+		For("UPDATE").
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.Money{}, ErrNothingToRefund
+	}
+	if err != nil {
+		return nil, models.Money{}, fmt.Errorf("failed to fetch payment: %w", err)
+	}
+
+	var refunded int64
+	err = tx.NewSelect().
+		Model((*models.Refund)(nil)).
+		ColumnExpr("COALESCE(SUM((amount).amount), 0)").
+		Where("payment_id = ?", payment.ID).
+		Scan(ctx, &refunded)
+
+	if err != nil {
+		return nil, models.Money{}, fmt.Errorf("failed to sum refunds: %w", err)
+	}
+
+	remaining := models.Money{Amount: payment.Amount.Amount - refunded, Currency: payment.Amount.Currency}
+	if remaining.Amount <= 0 {
+		return nil, models.Money{}, ErrNothingToRefund
+	}
+
+	return &payment, remaining, nil
+}
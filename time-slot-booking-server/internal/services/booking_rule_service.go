@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"time-slot-booking-server/internal/db"
+	"time-slot-booking-server/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type BookingRuleService struct {
+	db *db.DB
+}
+
+func NewBookingRuleService(database *db.DB) *BookingRuleService {
+	return &BookingRuleService{db: database}
+}
+
+// BookingRuleError is returned when booking rules reject a booking. It
+// lists every limit the booking would break.
+type BookingRuleError struct {
+	Violations []models.RuleViolation
+}
+
+func (e *BookingRuleError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+	return "booking not allowed: " + strings.Join(messages, "; ")
+}
+
+func (s *BookingRuleService) GetAll(ctx context.Context) ([]models.BookingRule, error) {
+	rules := make([]models.BookingRule, 0)
+
+	err := s.db.NewSelect().
+		Model(&rules).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	return rules, err
+}
+
+func (s *BookingRuleService) Create(ctx context.Context, req *models.CreateBookingRuleRequest) (*models.BookingRule, error) {
+	rule := newBookingRule(req)
+	if err := validateBookingRule(rule); err != nil {
+		return nil, err
+	}
+
+	_, err := s.db.NewInsert().
+		Model(rule).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create booking rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// Update replaces the scope and limits of a rule.
+func (s *BookingRuleService) Update(ctx context.Context, id uuid.UUID, req *models.CreateBookingRuleRequest) (*models.BookingRule, error) {
+	rule := newBookingRule(req)
+	if err := validateBookingRule(rule); err != nil {
+		return nil, err
+	}
+	rule.ID = id
+
+	result, err := s.db.NewUpdate().
+		Model(rule).
+		ExcludeColumn("id", "created_at").
+		Set("updated_at = NOW()").
+		WherePK().
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update booking rule: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, fmt.Errorf("booking rule not found")
+	}
+
+	return rule, nil
+}
+
+func (s *BookingRuleService) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.NewDelete().
+		Model((*models.BookingRule)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	return err
+}
+
+func newBookingRule(req *models.CreateBookingRuleRequest) *models.BookingRule {
+	return &models.BookingRule{
+		Scope:             req.Scope,
+		ResourceType:      req.ResourceType,
+		ResourceID:        req.ResourceID,
+		MinNoticeMinutes:  req.MinNoticeMinutes,
+		MaxAdvanceDays:    req.MaxAdvanceDays,
+		MaxActiveBookings: req.MaxActiveBookings,
+		MaxHoursPerWeek:   req.MaxHoursPerWeek,
+		OnePerDay:         req.OnePerDay,
+	}
+}
+
+// validateBookingRule checks the rule's scope and that the limits make
+// sense.
+func validateBookingRule(r *models.BookingRule) error {
+	if err := resolveScope(&r.Scope, r.ResourceType, r.ResourceID); err != nil {
+		return err
+	}
+
+	if r.MinNoticeMinutes != nil && *r.MinNoticeMinutes < 0 {
+		return fmt.Errorf("min_notice_minutes must not be negative")
+	}
+	if r.MaxAdvanceDays != nil && *r.MaxAdvanceDays < 1 {
+		return fmt.Errorf("max_advance_days must be at least 1")
+	}
+	if r.MaxActiveBookings != nil && *r.MaxActiveBookings < 1 {
+		return fmt.Errorf("max_active_bookings must be at least 1")
+	}
+	if r.MaxHoursPerWeek != nil && *r.MaxHoursPerWeek <= 0 {
+		return fmt.Errorf("max_hours_per_week must be positive")
+	}
+
+	return nil
+}
+
+// resolveScope infers the scope of a rule from its target when it is not
+// given, and checks that scope and target agree.
+func resolveScope(scope *string, resourceType string, resourceID *uuid.UUID) error {
+	if *scope == "" {
+		switch {
+		case resourceID != nil:
+			*scope = "resource"
+		case resourceType != "":
+			*scope = "resource_type"
+		default:
+			*scope = "global"
+		}
+	}
+
+	switch *scope {
+	case "global":
+		if resourceID != nil || resourceType != "" {
+			return fmt.Errorf("global rules cannot target a resource or resource type")
+		}
+	case "resource_type":
+		if resourceType == "" || resourceID != nil {
+			return fmt.Errorf("resource_type rules need resource_type and no resource_id")
+		}
+	case "resource":
+		if resourceID == nil || resourceType != "" {
+			return fmt.Errorf("resource rules need resource_id and no resource_type")
+		}
+	default:
+		return fmt.Errorf("invalid scope %q: must be global, resource_type or resource", *scope)
+	}
+
+	return nil
+}
+
+// checkBookingRules evaluates every rule that applies to the resource
+// against a booking by the user from start to end, and returns a
+// *BookingRuleError listing all violations. excludeID, when set, is a
+// booking being moved, which does not count against the quotas.
+func checkBookingRules(ctx context.Context, tx bun.Tx, userID, resourceID uuid.UUID, start, end time.Time, excludeID uuid.UUID) error {
+	var resource models.Resource
+	err := tx.NewSelect().
+		Model(&resource).
+		Column("id", "type", "time_zone").
+		Where("id = ?", resourceID).
+		Scan(ctx)
+
+	if err != nil {
+		return fmt.Errorf("resource not found: %w", err)
+	}
+
+	rules, err := applicableRules(ctx, tx, &resource)
+	if err != nil {
+		return err
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	// Quotas count the user's other bookings; lock the user so two
+	// concurrent bookings cannot both squeeze under a limit
+	_, err = tx.NewSelect().
+		Model((*models.AppUser)(nil)).
+		Column("id").
+		Where("id = ?", userID).
+		For("UPDATE").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	now := time.Now()
+	loc := resource.TimeLocation()
+	violations := make([]models.RuleViolation, 0)
+	violate := func(rule *models.BookingRule, code string, limit float64, format string, args ...interface{}) {
+		violations = append(violations, models.RuleViolation{
+			RuleID:  rule.ID,
+			Code:    code,
+			Message: fmt.Sprintf(format, args...),
+			Limit:   limit,
+		})
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+
+		if rule.MinNoticeMinutes != nil && start.Sub(now) < time.Duration(*rule.MinNoticeMinutes)*time.Minute {
+			violate(rule, "min_notice", float64(*rule.MinNoticeMinutes),
+				"bookings must be made at least %d minutes in advance", *rule.MinNoticeMinutes)
+		}
+
+		if rule.MaxAdvanceDays != nil && start.After(now.AddDate(0, 0, *rule.MaxAdvanceDays)) {
+			violate(rule, "max_advance", float64(*rule.MaxAdvanceDays),
+				"bookings can be made at most %d days in advance", *rule.MaxAdvanceDays)
+		}
+
+		if rule.MaxActiveBookings != nil {
+			active, err := userBookings(tx, rule, userID, excludeID).
+				Where("b.end_time > NOW()").
+				Count(ctx)
+
+			if err != nil {
+				return fmt.Errorf("failed to count active bookings: %w", err)
+			}
+			if active+1 > *rule.MaxActiveBookings {
+				violate(rule, "max_active_bookings", float64(*rule.MaxActiveBookings),
+					"at most %d active bookings are allowed", *rule.MaxActiveBookings)
+			}
+		}
+
+		if rule.MaxHoursPerWeek != nil {
+			weekStart, weekEnd := localWeek(start, loc)
+
+			var booked float64
+			err := userBookings(tx, rule, userID, excludeID).
+				ColumnExpr("COALESCE(SUM(EXTRACT(EPOCH FROM LEAST(b.end_time, ?) - GREATEST(b.start_time, ?))), 0) / 3600", weekEnd, weekStart).
+				Where("b.start_time < ?", weekEnd).
+				Where("b.end_time > ?", weekStart).
+				Scan(ctx, &booked)
+
+			if err != nil {
+				return fmt.Errorf("failed to sum booked hours: %w", err)
+			}
+
+			requested := minTime(end, weekEnd).Sub(maxTime(start, weekStart)).Hours()
+			if booked+requested > *rule.MaxHoursPerWeek {
+				violate(rule, "max_hours_per_week", *rule.MaxHoursPerWeek,
+					"at most %g hours per week are allowed; %g are already booked", *rule.MaxHoursPerWeek, booked)
+			}
+		}
+
+		if rule.OnePerDay {
+			dayStart := startOfDay(start.In(loc))
+			taken, err := userBookings(tx, rule, userID, excludeID).
+				Where("b.resource_id = ?", resourceID).
+				Where("b.start_time < ?", dayStart.AddDate(0, 0, 1)).
+				Where("b.end_time > ?", dayStart).
+				Exists(ctx)
+
+			if err != nil {
+				return fmt.Errorf("failed to check bookings on the day: %w", err)
+			}
+			if taken {
+				violate(rule, "one_per_day", 1, "only one booking per day is allowed on this resource")
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &BookingRuleError{Violations: violations}
+	}
+
+	return nil
+}
+
+// applicableRules returns the booking rules that apply to a resource: the
+// global ones, those for its type and its own, oldest first.
+func applicableRules(ctx context.Context, idb bun.IDB, resource *models.Resource) ([]models.BookingRule, error) {
+	rules := make([]models.BookingRule, 0)
+	err := idb.NewSelect().
+		Model(&rules).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("scope = 'global'").
+				WhereOr("scope = 'resource_type' AND resource_type = ?", resource.Type).
+				WhereOr("scope = 'resource' AND resource_id = ?", resource.ID)
+		}).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch booking rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// bookingWindow returns the start times the notice and advance rules allow
+// a booking made at now: from the longest minimum notice on and, when a
+// rule limits how far ahead bookings go, up to the shortest advance window.
+func bookingWindow(rules []models.BookingRule, now time.Time) (time.Time, *time.Time) {
+	earliest := now
+	var latest *time.Time
+
+	for i := range rules {
+		if notice := rules[i].MinNoticeMinutes; notice != nil {
+			earliest = maxTime(earliest, now.Add(time.Duration(*notice)*time.Minute))
+		}
+		if days := rules[i].MaxAdvanceDays; days != nil {
+			limit := now.AddDate(0, 0, *days)
+			if latest == nil || limit.Before(*latest) {
+				latest = &limit
+			}
+		}
+	}
+
+	return earliest, latest
+}
+
+// userBookings selects the user's active bookings, aliased b, on the
+// resources a rule covers.
+func userBookings(tx bun.Tx, rule *models.BookingRule, userID, excludeID uuid.UUID) *bun.SelectQuery {
+	query := tx.NewSelect().
+		TableExpr("bookings AS b").
+		Where("b.user_id = ?", userID).
+		Where("b.status IN ('pending', 'confirmed')").
+		Where("b.id != ?", excludeID)
+
+	switch rule.Scope {
+	case "resource":
+		query = query.Where("b.resource_id = ?", *rule.ResourceID)
+	case "resource_type":
+		query = query.Where("b.resource_id IN (SELECT id FROM resources WHERE type = ?)", rule.ResourceType)
+	}
+
+	return query
+}
+
+// localWeek returns the Monday-to-Monday week containing t in loc.
+func localWeek(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	day := startOfDay(t.In(loc))
+	monday := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	return monday, monday.AddDate(0, 0, 7)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
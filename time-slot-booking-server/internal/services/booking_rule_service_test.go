@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"time-slot-booking-server/internal/models"
+)
+
+func TestBookingWindow(t *testing.T) {
+	now := utc(2025, 6, 2, 9, 0)
+	limit := func(n int) *int { return &n }
+
+	tests := []struct {
+		name         string
+		rules        []models.BookingRule
+		wantEarliest time.Time
+		wantLatest   *time.Time
+	}{
+		{
+			name:         "no rules leave the window open",
+			wantEarliest: now,
+		},
+		{
+			name: "longest notice and shortest advance window apply",
+			rules: []models.BookingRule{
+				{MinNoticeMinutes: limit(60), MaxAdvanceDays: limit(30)},
+				{MinNoticeMinutes: limit(120)},
+				{MaxAdvanceDays: limit(7)},
+			},
+			wantEarliest: utc(2025, 6, 2, 11, 0),
+			wantLatest:   ptrTime(utc(2025, 6, 9, 9, 0)),
+		},
+		{
+			name:         "quota rules do not narrow the window",
+			rules:        []models.BookingRule{{MaxActiveBookings: limit(2)}},
+			wantEarliest: now,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			earliest, latest := bookingWindow(tt.rules, now)
+
+			if !earliest.Equal(tt.wantEarliest) {
+				t.Errorf("earliest = %s, want %s", earliest, tt.wantEarliest)
+			}
+
+			switch {
+			case tt.wantLatest == nil && latest != nil:
+				t.Errorf("latest = %s, want none", *latest)
+			case tt.wantLatest != nil && (latest == nil || !latest.Equal(*tt.wantLatest)):
+				t.Errorf("latest = %v, want %s", latest, *tt.wantLatest)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"time-slot-booking-server/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// ErrSeriesConflict is returned by CreateSeries when nothing was booked:
+// no occurrence was available, or an all-or-nothing series had a conflict.
+// The returned report lists the conflicts.
+var ErrSeriesConflict = errors.New("series could not be booked")
+
+// CreateSeries books every occurrence of a recurrence rule starting at the
+// given time slot. Occurrences use the resource's slot at the same local
+// time and length. Unless AllOrNothing is set, occurrences that cannot be
+// booked are reported and skipped. Each priced occurrence is paid for on
+// its own, like a single booking.
+func (s *BookingService) CreateSeries(ctx context.Context, userID uuid.UUID, req *models.CreateBookingSeriesRequest) (*models.BookingSeriesResponse, error) {
+	rule, err := models.ParseRecurrenceRule(req.Rule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule: %w", err)
+	}
+
+	var resource models.Resource
+	err = s.db.NewSelect().
+		Model(&resource).
+		Where("id = ?", req.ResourceID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("resource not found: %w", err)
+	}
+
+	var first models.TimeSlot
+	err = s.db.NewSelect().
+		Model(&first).
+		Where("id = ?", req.TimeSlotID).
+		Where("resource_id = ?", req.ResourceID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("time slot not found: %w", err)
+	}
+
+	starts := rule.Occurrences(first.StartTime, resource.TimeLocation())
+	length := first.EndTime.Sub(first.StartTime)
+
+	response := &models.BookingSeriesResponse{
+		Occurrences: make([]models.SeriesOccurrence, 0, len(starts)),
+	}
+
+	var booked []*models.Booking
+	err = s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := requireGoodStanding(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		if err := requireStandalone(ctx, tx, req.ResourceID); err != nil {
+			return err
+		}
+
+		series := &models.BookingSeries{
+			UserID:     userID,
+			ResourceID: req.ResourceID,
+			Rule:       req.Rule,
+			Notes:      req.Notes,
+			Status:     "active",
+		}
+
+		_, err := tx.NewInsert().
+			Model(series).
+			Returning("*").
+			Exec(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to create series: %w", err)
+		}
+
+		for _, start := range starts {
+			occurrence := models.SeriesOccurrence{
+				StartTime: start,
+				EndTime:   start.Add(length),
+				Status:    "conflict",
+			}
+
+			booking, err := s.bookOccurrence(ctx, tx, userID, series, &occurrence, req.PayWithCredits)
+			if err != nil {
+				occurrence.Reason = err.Error()
+			} else {
+				occurrence.Status = "booked"
+				occurrence.BookingID = &booking.ID
+				booked = append(booked, booking)
+			}
+
+			response.Occurrences = append(response.Occurrences, occurrence)
+		}
+
+		if len(booked) == 0 || (req.AllOrNothing && len(booked) < len(starts)) {
+			return ErrSeriesConflict
+		}
+
+		response.Series = series
+		return nil
+	})
+
+	if errors.Is(err, ErrSeriesConflict) {
+		// Nothing was kept; report the conflicts without booking IDs
+		for i := range response.Occurrences {
+			response.Occurrences[i].BookingID = nil
+			if response.Occurrences[i].Status == "booked" {
+				response.Occurrences[i].Status = "available"
+			}
+		}
+		return response, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.openPayments(ctx, booked...)
+	return response, nil
+}
+
+// bookOccurrence books and pays for the slot matching one occurrence inside
+// a savepoint, so a conflict, or a lack of credits, only undoes that
+// occurrence.
+func (s *BookingService) bookOccurrence(ctx context.Context, tx bun.Tx, userID uuid.UUID, series *models.BookingSeries, occurrence *models.SeriesOccurrence, withCredits bool) (*models.Booking, error) {
+	var booking *models.Booking
+
+	err := tx.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		timeSlotID, err := findSlotAt(ctx, tx, series.ResourceID, occurrence.StartTime, occurrence.EndTime)
+		if err != nil {
+			return err
+		}
+		occurrence.TimeSlotID = &timeSlotID
+
+		if !occurrence.StartTime.After(time.Now()) {
+			return fmt.Errorf("time slot has already started")
+		}
+
+		booking, err = s.bookSlot(ctx, tx, userID, series.ResourceID, timeSlotID, series.Notes, 1, nil)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.Booking)(nil)).
+			Set("series_id = ?", series.ID).
+			Where("id = ?", booking.ID).
+			Exec(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to link booking to series: %w", err)
+		}
+		booking.SeriesID = &series.ID
+
+		if withCredits {
+			return payWithCredits(ctx, tx, booking)
+		}
+
+		return s.requirePayment(ctx, tx, booking)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return booking, nil
+}
+
+// GetUserSeries returns the user's booking series, newest first.
+func (s *BookingService) GetUserSeries(ctx context.Context, userID uuid.UUID) ([]models.BookingSeries, error) {
+	series := make([]models.BookingSeries, 0)
+
+	err := s.db.NewSelect().
+		Model(&series).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Scan(ctx)
+
+	return series, err
+}
+
+// GetSeries returns one of the user's series with its bookings in slot order.
+func (s *BookingService) GetSeries(ctx context.Context, seriesID, userID uuid.UUID) (*models.BookingSeriesResponse, error) {
+	var series models.BookingSeries
+	err := s.db.NewSelect().
+		Model(&series).
+		Where("id = ?", seriesID).
+		Where("user_id = ?", userID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bookings := make([]models.Booking, 0)
+	err = s.db.NewSelect().
+		Model(&bookings).
+		Where("series_id = ?", seriesID).
+		Order("start_time ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch series bookings: %w", err)
+	}
+
+	return &models.BookingSeriesResponse{Series: &series, Bookings: bookings}, nil
+}
+
+// CancelSeries cancels one occurrence, an occurrence and every later one, or
+// all remaining occurrences of the user's series, each under its resource's
+// cancellation policy as Cancel would. Occurrences that have already
+// started are left alone. It returns the cancelled bookings.
+func (s *BookingService) CancelSeries(ctx context.Context, seriesID, userID uuid.UUID, req *models.CancelSeriesRequest) ([]models.Booking, error) {
+	if req.Scope != "all" && req.BookingID == nil {
+		return nil, fmt.Errorf("booking_id is required for scope %q", req.Scope)
+	}
+
+	cancelled := make([]models.Booking, 0)
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var series models.BookingSeries
+		err := tx.NewSelect().
+			Model(&series).
+			Where("id = ?", seriesID).
+			Where("user_id = ?", userID).
+			For("UPDATE").
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("series not found: %w", err)
+		}
+
+		query := tx.NewSelect().
+			Model(&cancelled).
+			Where("series_id = ?", seriesID).
+			Where("status IN ('pending', 'confirmed')").
+			Where("start_time > NOW()").
+			Order("start_time ASC").
+			For("UPDATE")
+
+		switch req.Scope {
+		case "occurrence":
+			query = query.Where("id = ?", *req.BookingID)
+		case "following":
+			query = query.Where("start_time >= (SELECT start_time FROM bookings WHERE id = ? AND series_id = ?)", *req.BookingID, seriesID)
+		case "all":
+		default:
+			return fmt.Errorf("scope must be occurrence, following or all")
+		}
+
+		if err := query.Scan(ctx); err != nil {
+			return fmt.Errorf("failed to find series bookings: %w", err)
+		}
+
+		if len(cancelled) == 0 {
+			return fmt.Errorf("no upcoming bookings to cancel")
+		}
+
+		reason := "series cancelled: " + req.Scope
+		for i := range cancelled {
+			quote, err := quoteCancellation(ctx, tx, &cancelled[i], false)
+			if err != nil {
+				return err
+			}
+			if !quote.Allowed {
+				return fmt.Errorf("cannot cancel: %s", quote.Reason)
+			}
+
+			if err := s.cancelOnTerms(ctx, tx, &cancelled[i], quote, &userID, reason); err != nil {
+				return err
+			}
+		}
+
+		if req.Scope != "all" {
+			return nil
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.BookingSeries)(nil)).
+			Set("status = ?", "cancelled").
+			Set("updated_at = NOW()").
+			Where("id = ?", seriesID).
+			Exec(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to cancel series: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range cancelled {
+		s.issueRefunds(ctx, &cancelled[i])
+	}
+
+	return cancelled, nil
+}
@@ -2,11 +2,14 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
+	"time-slot-booking-server/internal/config"
 	"time-slot-booking-server/internal/db"
 	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/payments"
 
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
@@ -14,106 +17,142 @@ import (
 
 type BookingService struct {
 	db *db.DB
+	// payments collects priced bookings; nil when payments are disabled
+	payments payments.Provider
 }
 
-func NewBookingService(database *db.DB) *BookingService {
-	return &BookingService{db: database}
+func NewBookingService(database *db.DB, provider payments.Provider) *BookingService {
+	return &BookingService{db: database, payments: provider}
 }
 
-func (s *BookingService) Create(ctx context.Context, userID, resourceID, timeSlotID uuid.UUID, notes string) (*models.Booking, error) {
-	// Use a transaction to ensure data consistency
-	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
-		// Check if time slot exists and is available
-		var timeSlot models.TimeSlot
-		err := tx.NewSelect().
-			Model(&timeSlot).
-			Where("id = ?", timeSlotID).
-			Where("resource_id = ?", resourceID).
-			Where("is_available = ?", true).
-			Scan(ctx)
+func (s *BookingService) Create(ctx context.Context, userID uuid.UUID, req *models.CreateBookingRequest) (*models.Booking, error) {
+	return s.book(ctx, userID, req, nil)
+}
 
-		if err != nil {
-			return fmt.Errorf("time slot not found or unavailable: %w", err)
-		}
+// Hold reserves seats as a pending booking while the user checks out. The
+// seats count against capacity until the hold is confirmed, cancelled or
+// expires.
+func (s *BookingService) Hold(ctx context.Context, userID uuid.UUID, req *models.CreateBookingRequest) (*models.Booking, error) {
+	expiresAt := time.Now().Add(config.AppConfig.BookingHoldTTL)
+	return s.book(ctx, userID, req, &expiresAt)
+}
 
-		// Check for overlapping bookings
-		var existingBooking models.Booking
-		err = tx.NewSelect().
-			Model(&existingBooking).
-			Where("time_slot_id = ?", timeSlotID).
-			Where("status IN ('pending', 'confirmed')").
-			Limit(1).
-			Scan(ctx)
+// book takes seats for the party in a time slot, as a confirmed booking or,
+// when holdExpiresAt is set, as a pending hold. Priced bookings stay
+// pending until they are paid when payments are enabled, unless they are
+// paid with credits.
+func (s *BookingService) book(ctx context.Context, userID uuid.UUID, req *models.CreateBookingRequest, holdExpiresAt *time.Time) (*models.Booking, error) {
+	partySize, err := partySizeOf(req.PartySize, req.Attendees)
+	if err != nil {
+		return nil, err
+	}
+
+	var booking *models.Booking
 
-		if err == nil {
-			return fmt.Errorf("time slot is already booked")
+	// Use a transaction to ensure data consistency
+	err = s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := requireGoodStanding(ctx, tx, userID); err != nil {
+			return err
 		}
 
-		// Get current booking count for capacity check
-		bookingCount, err := tx.NewSelect().
-			Table("bookings").
-			Where("time_slot_id = ?", timeSlotID).
-			Where("status IN ('pending', 'confirmed')").
-			Count(ctx)
+		if err := requireStandalone(ctx, tx, req.ResourceID); err != nil {
+			return err
+		}
 
+		var err error
+		booking, err = s.bookSlot(ctx, tx, userID, req.ResourceID, req.TimeSlotID, req.Notes, partySize, holdExpiresAt)
 		if err != nil {
-			return fmt.Errorf("failed to check capacity: %w", err)
+			return err
 		}
 
-		if bookingCount >= timeSlot.Capacity {
-			return fmt.Errorf("time slot is at full capacity")
+		if err := checkQuotedTotal(booking, req.QuotedTotal); err != nil {
+			return err
 		}
 
-		// Create the booking
-		booking := &models.Booking{
-			UserID:      userID,
-			ResourceID:  resourceID,
-			TimeSlotID:  timeSlotID,
-			Status:      "confirmed",
-			Notes:       notes,
-			TotalAmount: timeSlot.Price,
+		if err := addAttendees(ctx, tx, booking, req.Attendees); err != nil {
+			return err
 		}
 
-		_, err = tx.NewInsert().
-			Model(booking).
-			Exec(ctx)
-
-		if err != nil {
-			return fmt.Errorf("failed to create booking: %w", err)
+		if req.PayWithCredits {
+			return payWithCredits(ctx, tx, booking)
 		}
 
-		// Update time slot availability if capacity reached
-		if bookingCount+1 >= timeSlot.Capacity {
-			_, err = tx.NewUpdate().
-				Model((*models.TimeSlot)(nil)).
-				Set("is_available = ?", false).
-				Where("id = ?", timeSlotID).
-				Exec(ctx)
+		return s.requirePayment(ctx, tx, booking)
+	})
 
-			if err != nil {
-				return fmt.Errorf("failed to update time slot availability: %w", err)
-			}
+	if err != nil {
+		return nil, err
+	}
+
+	s.openPayments(ctx, booking)
+	return booking, nil
+}
+
+// bookSlot checks that a time slot has seats for the party and books them
+// within the caller's transaction.
+func (s *BookingService) bookSlot(ctx context.Context, tx bun.Tx, userID, resourceID, timeSlotID uuid.UUID, notes string, seats int, holdExpiresAt *time.Time) (*models.Booking, error) {
+	// Lock the time slot row so concurrent bookings for the same slot
+	// are serialized until this transaction commits
+	timeSlot, err := lockTimeSlot(ctx, tx, timeSlotID)
+	if err != nil {
+		return nil, fmt.Errorf("time slot not found or unavailable: %w", err)
+	}
+
+	// Seats of lapsed holds the sweeper has not reached yet are free
+	released, err := s.expireHolds(ctx, tx, &timeSlotID)
+	if err != nil {
+		return nil, err
+	}
+	if released > 0 {
+		if timeSlot, err = lockTimeSlot(ctx, tx, timeSlotID); err != nil {
+			return nil, fmt.Errorf("time slot not found or unavailable: %w", err)
 		}
+	}
 
-		return nil
-	})
+	return bookLockedSlot(ctx, tx, userID, resourceID, timeSlot, notes, seats, holdExpiresAt)
+}
 
+// bookLockedSlot is bookSlot for a time slot the caller has already locked,
+// without sweeping its lapsed holds first.
+func bookLockedSlot(ctx context.Context, tx bun.Tx, userID, resourceID uuid.UUID, timeSlot *models.TimeSlot, notes string, seats int, holdExpiresAt *time.Time) (*models.Booking, error) {
+	if timeSlot.ResourceID != resourceID || !timeSlot.IsAvailable {
+		return nil, fmt.Errorf("time slot not found or unavailable")
+	}
+
+	if !timeSlot.StartTime.After(time.Now()) {
+		return nil, fmt.Errorf("time slot has already started")
+	}
+
+	blackedOut, err := slotBlackedOut(ctx, tx, timeSlot.ID)
 	if err != nil {
 		return nil, err
 	}
+	if blackedOut {
+		return nil, fmt.Errorf("time slot is closed by a blackout period")
+	}
 
-	// Return the created booking
-	var booking models.Booking
-	err = s.db.NewSelect().
-		Model(&booking).
-		Where("user_id = ?", userID).
-		Where("resource_id = ?", resourceID).
-		Where("time_slot_id = ?", timeSlotID).
-		Order("created_at DESC").
-		Limit(1).
-		Scan(ctx)
+	if err := checkBookingRules(ctx, tx, userID, resourceID, timeSlot.StartTime, timeSlot.EndTime, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	if free := timeSlot.Capacity - timeSlot.BookedCount; seats > free {
+		if free <= 0 {
+			return nil, fmt.Errorf("time slot is at full capacity")
+		}
+		return nil, fmt.Errorf("time slot has only %d seats left", free)
+	}
 
-	return &booking, err
+	booking, err := insertBooking(ctx, tx, userID, timeSlot, notes, seats, holdExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Take the seats; the time_slot_capacity constraint rejects overbooking
+	if err := reserveSeats(ctx, tx, timeSlot.ID, seats); err != nil {
+		return nil, err
+	}
+
+	return booking, nil
 }
 
 func (s *BookingService) GetUserBookings(ctx context.Context, userID uuid.UUID) ([]models.Booking, error) {
@@ -134,7 +173,9 @@ func (s *BookingService) GetUserBookings(ctx context.Context, userID uuid.UUID)
 	return bookings, err
 }
 
-func (s *BookingService) GetByID(ctx context.Context, bookingID uuid.UUID) (*models.Booking, error) {
+// GetByID returns a booking with its attendees. Users see their own
+// bookings, providers those on resources assigned to them, admins any.
+func (s *BookingService) GetByID(ctx context.Context, bookingID, userID uuid.UUID, role string) (*models.Booking, error) {
 	var booking models.Booking
 
 	err := s.db.NewSelect().
@@ -146,82 +187,628 @@ func (s *BookingService) GetByID(ctx context.Context, bookingID uuid.UUID) (*mod
 		return nil, err
 	}
 
+	allowed, err := canView(ctx, s.db, &booking, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("booking not found")
+	}
+
+	if err := loadAttendees(ctx, s.db, &booking); err != nil {
+		return nil, err
+	}
+
 	return &booking, nil
 }
 
-func (s *BookingService) Cancel(ctx context.Context, bookingID uuid.UUID, userID uuid.UUID) error {
-	return s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
-		// Get booking details
-		var booking models.Booking
+// Reschedule moves an active booking to another time slot, on the same
+// resource or another resource of the same type, in one transaction. The
+// booking keeps its ID and history; it is repriced for the new slot under
+// the pricing rules in force now. Paid bookings only move to slots at the
+// same price and unpaid ones only to free slots, while payments are
+// enabled; bookings paid with credits only move to resources their pack
+// covers.
+func (s *BookingService) Reschedule(ctx context.Context, bookingID, userID, timeSlotID uuid.UUID) (*models.Booking, error) {
+	var booking models.Booking
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
 		err := tx.NewSelect().
 			Model(&booking).
 			Where("id = ?", bookingID).
 			Where("user_id = ?", userID).
+			For("UPDATE").
 			Scan(ctx)
 
 		if err != nil {
 			return fmt.Errorf("booking not found: %w", err)
 		}
 
-		// Update booking status
+		if booking.Status != "pending" && booking.Status != "confirmed" {
+			return fmt.Errorf("cannot reschedule a %s booking", booking.Status)
+		}
+
+		if err := requireGoodStanding(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		if booking.TimeSlotID == nil {
+			return fmt.Errorf("only time slot bookings can be rescheduled")
+		}
+
+		if booking.BundleID != nil {
+			return fmt.Errorf("bookings in a bundle cannot be rescheduled on their own")
+		}
+
+		if booking.PaymentStatus == awaitingPayment {
+			return fmt.Errorf("booking is awaiting payment and cannot be rescheduled")
+		}
+
+		if *booking.TimeSlotID == timeSlotID {
+			return fmt.Errorf("booking is already in this time slot")
+		}
+
+		// Lock both slots in a fixed order so two opposite reschedules
+		// cannot deadlock
+		oldSlot, newSlot, err := lockTimeSlotPair(ctx, tx, *booking.TimeSlotID, timeSlotID)
+		if err != nil {
+			return fmt.Errorf("time slot not found or unavailable: %w", err)
+		}
+
+		// Seats of lapsed holds the sweeper has not reached yet are free
+		released, err := s.expireHolds(ctx, tx, &timeSlotID)
+		if err != nil {
+			return err
+		}
+		if released > 0 {
+			if newSlot, err = lockTimeSlot(ctx, tx, timeSlotID); err != nil {
+				return fmt.Errorf("time slot not found or unavailable: %w", err)
+			}
+		}
+
+		if !newSlot.IsAvailable || !newSlot.StartTime.After(time.Now()) {
+			return fmt.Errorf("time slot not found or unavailable")
+		}
+
+		if newSlot.ResourceID != oldSlot.ResourceID {
+			sameType, err := tx.NewSelect().
+				TableExpr("resources AS r_old").
+				Join("JOIN resources AS r_new ON r_new.type = r_old.type").
+				Where("r_old.id = ?", oldSlot.ResourceID).
+				Where("r_new.id = ?", newSlot.ResourceID).
+				Exists(ctx)
+
+			if err != nil {
+				return fmt.Errorf("failed to compare resources: %w", err)
+			}
+			if !sameType {
+				return fmt.Errorf("can only reschedule to a resource of the same type")
+			}
+		}
+
+		if err := requireStandalone(ctx, tx, newSlot.ResourceID); err != nil {
+			return err
+		}
+
+		blackedOut, err := slotBlackedOut(ctx, tx, timeSlotID)
+		if err != nil {
+			return err
+		}
+		if blackedOut {
+			return fmt.Errorf("time slot is closed by a blackout period")
+		}
+
+		if err := checkBookingRules(ctx, tx, userID, newSlot.ResourceID, newSlot.StartTime, newSlot.EndTime, booking.ID); err != nil {
+			return err
+		}
+
+		if newSlot.BookedCount+booking.PartySize > newSlot.Capacity {
+			return fmt.Errorf("time slot does not have %d free seats", booking.PartySize)
+		}
+
+		quote, err := priceBooking(ctx, tx, userID, newSlot.ResourceID, newSlot.StartTime, newSlot.EndTime, newSlot.Price, booking.PartySize)
+		if err != nil {
+			return err
+		}
+
+		// A booking is charged, refunded and reported in one currency
+		if booking.TotalAmount != nil && quote.Total != nil && quote.Total.Currency != booking.TotalAmount.Currency {
+			return fmt.Errorf("cannot reschedule a booking priced in %s to a slot priced in %s", booking.TotalAmount.Currency, quote.Total.Currency)
+		}
+
+		// What was captured is neither topped up nor partly refunded, so a
+		// paid booking only moves to a slot at the same price
+		if booking.PaymentStatus == "paid" && !samePrice(booking.TotalAmount, quote.Total) {
+			return fmt.Errorf("paid booking can only be rescheduled to a slot at the same price; cancel and rebook instead")
+		}
+
+		if booking.CreditsUsed > 0 {
+			if err := requirePackCovers(ctx, tx, &booking, newSlot.ResourceID); err != nil {
+				return err
+			}
+		} else if s.payments != nil && booking.PaymentStatus != "paid" && quote.Total != nil && quote.Total.Amount > 0 {
+			// Nothing was paid for this booking, and a priced slot is only
+			// confirmed once paid for
+			return fmt.Errorf("unpaid booking can only be rescheduled to a free slot; cancel and rebook instead")
+		}
+
+		status := booking.Status
+		if newSlot.ResourceID != booking.ResourceID && booking.HoldExpiresAt == nil {
+			requiresApproval, err := resourceRequiresApproval(ctx, tx, newSlot.ResourceID)
+			if err != nil {
+				return err
+			}
+			// A request approved for one resource does not carry over to another
+			if requiresApproval {
+				status = "pending"
+			}
+		}
+
 		_, err = tx.NewUpdate().
 			Model(&booking).
-			Set("status = ?", "cancelled").
+			Set("resource_id = ?", newSlot.ResourceID).
+			Set("time_slot_id = ?", newSlot.ID).
+			Set("start_time = ?", newSlot.StartTime).
+			Set("end_time = ?", newSlot.EndTime).
+			Set("total_amount = ?", quote.Total).
+			Set("price_breakdown = ?", quote.Lines).
 			Set("updated_at = NOW()").
-			Where("id = ?", bookingID).
+			Where("id = ?", booking.ID).
+			Returning("*").
 			Exec(ctx)
 
 		if err != nil {
-			return fmt.Errorf("failed to cancel booking: %w", err)
+			return fmt.Errorf("failed to reschedule booking: %w", err)
+		}
+
+		reason := fmt.Sprintf("rescheduled from time slot %s to %s", oldSlot.ID, newSlot.ID)
+		if status != booking.Status {
+			err = setBookingStatus(ctx, tx, &booking, status, &userID, reason)
+		} else {
+			err = recordTransition(ctx, tx, booking.ID, booking.Status, booking.Status, &userID, reason)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := reserveSeats(ctx, tx, newSlot.ID, booking.PartySize); err != nil {
+			return err
+		}
+
+		if err := releaseSeats(ctx, tx, oldSlot.ID, booking.PartySize); err != nil {
+			return err
+		}
+
+		// Hand the freed seat to the head of the old slot's waitlist
+		return s.promoteWaitlist(ctx, tx, oldSlot.ID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &booking, nil
+}
+
+// ConfirmHold turns the user's unexpired hold into a confirmed booking, or
+// into an approval request on resources that require one. The seat is
+// already taken, so capacity is not checked again. Holds awaiting payment
+// are confirmed by the payment instead.
+func (s *BookingService) ConfirmHold(ctx context.Context, bookingID, userID uuid.UUID) (*models.Booking, error) {
+	var booking models.Booking
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		err := tx.NewSelect().
+			Model(&booking).
+			Where("id = ?", bookingID).
+			Where("user_id = ?", userID).
+			For("UPDATE").
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("booking not found: %w", err)
 		}
 
-		// Re-enable time slot if capacity was reached
-		var timeSlot models.TimeSlot
+		if booking.Status != "pending" || booking.HoldExpiresAt == nil {
+			return fmt.Errorf("booking is not an active hold")
+		}
+
+		if !booking.HoldExpiresAt.After(time.Now()) {
+			return fmt.Errorf("hold has expired")
+		}
+
+		if booking.PaymentStatus == awaitingPayment {
+			return fmt.Errorf("booking is awaiting payment")
+		}
+
+		return confirmHold(ctx, tx, &booking, &userID, "hold confirmed")
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &booking, nil
+}
+
+// confirmHold clears the expiry of a hold the caller has locked and
+// confirms it, or leaves it pending for review on resources that require
+// approval.
+func confirmHold(ctx context.Context, tx bun.Tx, booking *models.Booking, actorID *uuid.UUID, reason string) error {
+	_, err := tx.NewUpdate().
+		Model((*models.Booking)(nil)).
+		Set("hold_expires_at = NULL").
+		Where("id = ?", booking.ID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to confirm hold: %w", err)
+	}
+	booking.HoldExpiresAt = nil
+
+	requiresApproval, err := resourceRequiresApproval(ctx, tx, booking.ResourceID)
+	if err != nil {
+		return err
+	}
+
+	// On restricted resources the booking stays pending for review
+	if requiresApproval {
+		return recordTransition(ctx, tx, booking.ID, booking.Status, booking.Status, actorID, reason+"; awaiting approval")
+	}
+
+	if err := setBookingStatus(ctx, tx, booking, "confirmed", actorID, reason); err != nil {
+		return fmt.Errorf("failed to confirm hold: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseExpiredHolds cancels holds past their expiry and frees their seats.
+// It returns the number of holds released.
+func (s *BookingService) ReleaseExpiredHolds(ctx context.Context) (int, error) {
+	released := 0
+	var refunded []models.Booking
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		released, err = s.expireHolds(ctx, tx, nil)
+		if err != nil {
+			return err
+		}
+
+		// Unpaid bundle components take the rest of the bundle with them,
+		// which needs the payment provider for refunds
+		var unpaid []models.Booking
 		err = tx.NewSelect().
-			Model(&timeSlot).
-			Where("id = ?", booking.TimeSlotID).
+			Model(&unpaid).
+			Where("status = ?", "pending").
+			Where("hold_expires_at <= NOW()").
+			Where("bundle_id IS NOT NULL").
+			Order("hold_expires_at ASC").
+			For("UPDATE SKIP LOCKED").
 			Scan(ctx)
 
-		if err == nil {
-			// Check current booking count
-			remainingBookings, err := tx.NewSelect().
-				Table("bookings").
-				Where("time_slot_id = ?", booking.TimeSlotID).
-				Where("status IN ('pending', 'confirmed')").
-				Count(ctx)
-
-			if err == nil && remainingBookings < timeSlot.Capacity {
-				// Re-enable the time slot
-				_, err = tx.NewUpdate().
-					Model((*models.TimeSlot)(nil)).
-					Set("is_available = ?", true).
-					Where("id = ?", booking.TimeSlotID).
-					Exec(ctx)
-
-				if err != nil {
-					return fmt.Errorf("failed to update time slot availability: %w", err)
-				}
+		if err != nil {
+			return fmt.Errorf("failed to find expired bundle payments: %w", err)
+		}
+
+		expiredBundles := make(map[uuid.UUID]bool)
+		for i := range unpaid {
+			if expiredBundles[*unpaid[i].BundleID] {
+				continue
+			}
+
+			bundle, err := s.expirePayment(ctx, tx, &unpaid[i], "expired", "payment not received in time")
+			if err != nil {
+				return fmt.Errorf("failed to release hold %s: %w", unpaid[i].ID, err)
 			}
+			refunded = append(refunded, bundle...)
+			expiredBundles[*unpaid[i].BundleID] = true
+			released++
 		}
 
 		return nil
 	})
+
+	if err != nil {
+		return released, err
+	}
+
+	for i := range refunded {
+		s.issueRefunds(ctx, &refunded[i])
+	}
+
+	return released, nil
 }
 
+// CheckConflicts reports whether the range overlaps an active booking on
+// the resource, whether booked by time slot or as a free-form interval.
 func (s *BookingService) CheckConflicts(ctx context.Context, resourceID uuid.UUID, startTime, endTime time.Time) error {
-	var conflicts []models.Booking
+	if !endTime.After(startTime) {
+		return fmt.Errorf("end_time must be after start_time")
+	}
 
-	// Find bookings that overlap with the requested time range
-	err := s.db.NewSelect().
-		Model(&conflicts).
+	return checkConflicts(ctx, s.db, resourceID, startTime, endTime)
+}
+
+func checkConflicts(ctx context.Context, idb bun.IDB, resourceID uuid.UUID, startTime, endTime time.Time) error {
+	// Half-open ranges: a booking ending at startTime does not conflict
+	conflict, err := idb.NewSelect().
+		Model((*models.Booking)(nil)).
 		Where("resource_id = ?", resourceID).
 		Where("status IN ('pending', 'confirmed')").
-		Where("(start_time <= ? AND end_time > ?)", startTime, startTime).
-		Where("(start_time < ? AND end_time >= ?)", endTime, endTime).
+		Where("start_time < ?", endTime).
+		Where("end_time > ?", startTime).
+		Exists(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to check conflicts: %w", err)
+	}
+
+	if conflict {
+		return ErrBookingConflict
+	}
+
+	return nil
+}
+
+// lockTimeSlot loads a time slot with a row lock held until the surrounding
+// transaction ends.
+func lockTimeSlot(ctx context.Context, tx bun.Tx, timeSlotID uuid.UUID) (*models.TimeSlot, error) {
+	var timeSlot models.TimeSlot
+	err := tx.NewSelect().
+		Model(&timeSlot).
+		Where("id = ?", timeSlotID).
+		For("UPDATE").
 		Scan(ctx)
 
-	if err == nil && len(conflicts) > 0 {
-		return fmt.Errorf("time slot conflicts with existing bookings")
+	if err != nil {
+		return nil, err
+	}
+
+	return &timeSlot, nil
+}
+
+// insertBooking records a booking for a time slot the caller has locked. It
+// is confirmed unless holdExpiresAt is set or the resource requires
+// approval, in which case it is pending. It does not take seats; callers
+// reserve partySize of them unless they are already held for the user.
+func insertBooking(ctx context.Context, tx bun.Tx, userID uuid.UUID, timeSlot *models.TimeSlot, notes string, partySize int, holdExpiresAt *time.Time) (*models.Booking, error) {
+	requiresApproval, err := resourceRequiresApproval(ctx, tx, timeSlot.ResourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := priceBooking(ctx, tx, userID, timeSlot.ResourceID, timeSlot.StartTime, timeSlot.EndTime, timeSlot.Price, partySize)
+	if err != nil {
+		return nil, err
+	}
+
+	booking := &models.Booking{
+		UserID:         userID,
+		ResourceID:     timeSlot.ResourceID,
+		TimeSlotID:     &timeSlot.ID,
+		StartTime:      timeSlot.StartTime,
+		EndTime:        timeSlot.EndTime,
+		Status:         "confirmed",
+		Notes:          notes,
+		PartySize:      partySize,
+		TotalAmount:    quote.Total,
+		PriceBreakdown: quote.Lines,
+		HoldExpiresAt:  holdExpiresAt,
+	}
+
+	if holdExpiresAt != nil || requiresApproval {
+		booking.Status = "pending"
+	}
+
+	_, err = tx.NewInsert().
+		Model(booking).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create booking: %w", err)
+	}
+
+	if err := recordTransition(ctx, tx, booking.ID, "", booking.Status, &userID, ""); err != nil {
+		return nil, err
+	}
+
+	return booking, nil
+}
+
+// cancelBooking cancels an active booking the caller has locked, gives its
+// seat back and hands it to the head of the waitlist, if any. A payment
+// still outstanding for it is cancelled and credits it was paid with are
+// returned.
+func (s *BookingService) cancelBooking(ctx context.Context, tx bun.Tx, booking *models.Booking, actorID *uuid.UUID, reason string) error {
+	if err := closePayment(ctx, tx, booking, "cancelled"); err != nil {
+		return err
+	}
+
+	if err := returnCredits(ctx, tx, booking, actorID, reason); err != nil {
+		return err
+	}
+
+	if err := setBookingStatus(ctx, tx, booking, "cancelled", actorID, reason); err != nil {
+		return fmt.Errorf("failed to cancel booking: %w", err)
+	}
+
+	return s.releaseBookingSeat(ctx, tx, booking)
+}
+
+// releaseBookingSeat gives the seats of a time slot booking back and hands
+// them to the head of the waitlist, if any. Interval bookings hold no seats.
+func (s *BookingService) releaseBookingSeat(ctx context.Context, tx bun.Tx, booking *models.Booking) error {
+	if booking.TimeSlotID == nil {
+		return nil
+	}
+
+	if err := releaseSeats(ctx, tx, *booking.TimeSlotID, booking.PartySize); err != nil {
+		return err
+	}
+
+	return s.promoteWaitlist(ctx, tx, *booking.TimeSlotID)
+}
+
+// setBookingStatus moves a booking to a new status and records the
+// transition. A nil actorID marks a change made by the system.
+func setBookingStatus(ctx context.Context, tx bun.Tx, booking *models.Booking, status string, actorID *uuid.UUID, reason string) error {
+	_, err := tx.NewUpdate().
+		Model((*models.Booking)(nil)).
+		Set("status = ?", status).
+		Set("updated_at = NOW()").
+		Where("id = ?", booking.ID).
+		Exec(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if err := recordTransition(ctx, tx, booking.ID, booking.Status, status, actorID, reason); err != nil {
+		return err
+	}
+
+	booking.Status = status
+	return nil
+}
+
+func recordTransition(ctx context.Context, tx bun.Tx, bookingID uuid.UUID, from, to string, actorID *uuid.UUID, reason string) error {
+	transition := &models.BookingTransition{
+		BookingID:  bookingID,
+		FromStatus: from,
+		ToStatus:   to,
+		ActorID:    actorID,
+		Reason:     reason,
+	}
+
+	_, err := tx.NewInsert().
+		Model(transition).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to record booking transition: %w", err)
+	}
+
+	return nil
+}
+
+func resourceRequiresApproval(ctx context.Context, tx bun.Tx, resourceID uuid.UUID) (bool, error) {
+	var requiresApproval bool
+	err := tx.NewSelect().
+		Model((*models.Resource)(nil)).
+		Column("requires_approval").
+		Where("id = ?", resourceID).
+		Scan(ctx, &requiresApproval)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch resource: %w", err)
+	}
+
+	return requiresApproval, nil
+}
+
+// expireHolds cancels lapsed holds, of one time slot or of all when
+// timeSlotID is nil, gives their seats back and offers them to the waitlist.
+// Bookings whose payment did not arrive in time are expired instead, except
+// bundle components, which ReleaseExpiredHolds expires with their bundle.
+func (s *BookingService) expireHolds(ctx context.Context, tx bun.Tx, timeSlotID *uuid.UUID) (int, error) {
+	var holds []models.Booking
+
+	query := tx.NewSelect().
+		Model(&holds).
+		Where("status = ?", "pending").
+		Where("hold_expires_at <= NOW()").
+		Where("bundle_id IS NULL").
+		Order("hold_expires_at ASC").
+		For("UPDATE SKIP LOCKED")
+
+	if timeSlotID != nil {
+		query = query.Where("time_slot_id = ?", *timeSlotID)
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return 0, fmt.Errorf("failed to find expired holds: %w", err)
+	}
+
+	for i := range holds {
+		var err error
+		if holds[i].PaymentStatus == awaitingPayment {
+			err = s.expireUnpaid(ctx, tx, &holds[i], "expired", "payment not received in time")
+		} else {
+			err = s.cancelBooking(ctx, tx, &holds[i], nil, "hold expired")
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to release hold %s: %w", holds[i].ID, err)
+		}
+	}
+
+	return len(holds), nil
+}
+
+// lockTimeSlotPair locks two time slots in ID order and returns them in the
+// order requested.
+func lockTimeSlotPair(ctx context.Context, tx bun.Tx, firstID, secondID uuid.UUID) (*models.TimeSlot, *models.TimeSlot, error) {
+	var slots []models.TimeSlot
+	err := tx.NewSelect().
+		Model(&slots).
+		Where("id IN (?)", bun.In([]uuid.UUID{firstID, secondID})).
+		Order("id ASC").
+		For("UPDATE").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var first, second *models.TimeSlot
+	for i := range slots {
+		switch slots[i].ID {
+		case firstID:
+			first = &slots[i]
+		case secondID:
+			second = &slots[i]
+		}
+	}
+
+	if first == nil || second == nil {
+		return nil, nil, sql.ErrNoRows
+	}
+
+	return first, second, nil
+}
+
+// reserveSeats increments the booked seat count of a time slot and marks it
+// unavailable once full. The database rejects the update if it would exceed
+// the slot's capacity (the time_slot_capacity constraint).
+func reserveSeats(ctx context.Context, tx bun.Tx, timeSlotID uuid.UUID, seats int) error {
+	_, err := tx.NewUpdate().
+		Model((*models.TimeSlot)(nil)).
+		Set("booked_count = booked_count + ?", seats).
+		Set("is_available = (NOT is_disabled AND booked_count + ? < capacity)", seats).
+		Where("id = ?", timeSlotID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update time slot availability: %w", err)
+	}
+
+	return nil
+}
+
+// releaseSeats decrements the booked seat count of a time slot and makes it
+// available again, unless an admin has disabled it.
+func releaseSeats(ctx context.Context, tx bun.Tx, timeSlotID uuid.UUID, seats int) error {
+	_, err := tx.NewUpdate().
+		Model((*models.TimeSlot)(nil)).
+		Set("booked_count = GREATEST(booked_count - ?, 0)", seats).
+		Set("is_available = (NOT is_disabled AND booked_count - ? < capacity)", seats).
+		Where("id = ?", timeSlotID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update time slot availability: %w", err)
 	}
 
 	return nil
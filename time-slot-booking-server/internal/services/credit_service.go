@@ -0,0 +1,466 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"time-slot-booking-server/internal/db"
+	"time-slot-booking-server/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// ErrInsufficientCredits is returned when none of the user's packs has
+// enough credits for a booking.
+var ErrInsufficientCredits = errors.New("not enough credits for this booking")
+
+type CreditService struct {
+	db *db.DB
+}
+
+func NewCreditService(database *db.DB) *CreditService {
+	return &CreditService{db: database}
+}
+
+func (s *CreditService) GetProducts(ctx context.Context) ([]models.CreditProduct, error) {
+	products := make([]models.CreditProduct, 0)
+
+	err := s.db.NewSelect().
+		Model(&products).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	return products, err
+}
+
+func (s *CreditService) CreateProduct(ctx context.Context, req *models.CreateCreditProductRequest) (*models.CreditProduct, error) {
+	product := newCreditProduct(req)
+	if err := product.Validate(); err != nil {
+		return nil, err
+	}
+
+	_, err := s.db.NewInsert().
+		Model(product).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create credit product: %w", err)
+	}
+
+	return product, nil
+}
+
+// UpdateProduct replaces a product. Packs already sold keep the terms they
+// were sold under.
+func (s *CreditService) UpdateProduct(ctx context.Context, id uuid.UUID, req *models.CreateCreditProductRequest) (*models.CreditProduct, error) {
+	product := newCreditProduct(req)
+	if err := product.Validate(); err != nil {
+		return nil, err
+	}
+	product.ID = id
+
+	result, err := s.db.NewUpdate().
+		Model(product).
+		ExcludeColumn("id", "created_at").
+		Set("updated_at = NOW()").
+		WherePK().
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update credit product: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, fmt.Errorf("credit product not found")
+	}
+
+	return product, nil
+}
+
+// DeleteProduct removes a product from sale. Packs already sold stay
+// usable.
+func (s *CreditService) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.NewDelete().
+		Model((*models.CreditProduct)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	return err
+}
+
+// RecordPurchase records the sale of an active product to a user, such as
+// a card sold at the front desk, and adds its credits to the user's
+// balance as a new pack.
+func (s *CreditService) RecordPurchase(ctx context.Context, adminID uuid.UUID, req *models.RecordCreditPurchaseRequest) (*models.CreditPack, error) {
+	var pack *models.CreditPack
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var product models.CreditProduct
+		err := tx.NewSelect().
+			Model(&product).
+			Where("id = ?", req.ProductID).
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("credit product not found: %w", err)
+		}
+		if !product.Active {
+			return fmt.Errorf("credit product is no longer sold")
+		}
+
+		exists, err := tx.NewSelect().
+			Model((*models.AppUser)(nil)).
+			Where("id = ?", req.UserID).
+			Exists(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch user: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("user not found")
+		}
+
+		pack = &models.CreditPack{
+			UserID:        req.UserID,
+			ProductID:     &product.ID,
+			Name:          product.Name,
+			ResourceTypes: product.ResourceTypes,
+			Credits:       product.Credits,
+			Remaining:     product.Credits,
+		}
+		if product.ValidityDays != nil {
+			expiresAt := time.Now().AddDate(0, 0, *product.ValidityDays)
+			pack.ExpiresAt = &expiresAt
+		}
+
+		_, err = tx.NewInsert().
+			Model(pack).
+			Returning("*").
+			Exec(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to create credit pack: %w", err)
+		}
+
+		note := req.Note
+		if note == "" {
+			note = product.Name
+		}
+
+		return recordCreditTransaction(ctx, tx, pack, nil, "purchase", pack.Credits, note, &adminID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pack, nil
+}
+
+// GetBalance returns the credits the user can still spend and the packs
+// they are in, soonest to expire first.
+func (s *CreditService) GetBalance(ctx context.Context, userID uuid.UUID) (*models.CreditBalance, error) {
+	packs := make([]models.CreditPack, 0)
+
+	err := s.db.NewSelect().
+		Model(&packs).
+		Where("user_id = ?", userID).
+		Where("remaining > 0").
+		Where("expires_at IS NULL OR expires_at > NOW()").
+		OrderExpr("expires_at ASC NULLS LAST, created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch credit packs: %w", err)
+	}
+
+	balance := &models.CreditBalance{Packs: packs}
+	for _, pack := range packs {
+		balance.Balance += pack.Remaining
+	}
+
+	return balance, nil
+}
+
+// GetTransactions returns the user's credits ledger, newest first.
+func (s *CreditService) GetTransactions(ctx context.Context, userID uuid.UUID) ([]models.CreditTransaction, error) {
+	transactions := make([]models.CreditTransaction, 0)
+
+	err := s.db.NewSelect().
+		Model(&transactions).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Scan(ctx)
+
+	return transactions, err
+}
+
+// ExpirePacks writes off the credits left in packs past their expiry. It
+// returns the number of packs expired.
+func (s *CreditService) ExpirePacks(ctx context.Context) (int, error) {
+	expired := 0
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var packs []models.CreditPack
+		err := tx.NewSelect().
+			Model(&packs).
+			Where("remaining > 0").
+			Where("expires_at <= NOW()").
+			For("UPDATE SKIP LOCKED").
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to find expired credit packs: %w", err)
+		}
+
+		for i := range packs {
+			pack := &packs[i]
+			if err := recordCreditTransaction(ctx, tx, pack, nil, "expiry", -pack.Remaining, "pack expired", nil); err != nil {
+				return err
+			}
+
+			_, err := tx.NewUpdate().
+				Model(pack).
+				Set("remaining = 0").
+				WherePK().
+				Exec(ctx)
+
+			if err != nil {
+				return fmt.Errorf("failed to expire credit pack %s: %w", pack.ID, err)
+			}
+		}
+
+		expired = len(packs)
+		return nil
+	})
+
+	return expired, err
+}
+
+func newCreditProduct(req *models.CreateCreditProductRequest) *models.CreditProduct {
+	product := &models.CreditProduct{
+		Name:          req.Name,
+		Description:   req.Description,
+		ResourceTypes: req.ResourceTypes,
+		Credits:       req.Credits,
+		Price:         req.Price,
+		ValidityDays:  req.ValidityDays,
+		Active:        true,
+	}
+	if product.ResourceTypes == nil {
+		product.ResourceTypes = []string{}
+	}
+	if req.Active != nil {
+		product.Active = *req.Active
+	}
+	return product
+}
+
+// payWithCredits pays for a new booking the caller has locked with one
+// credit per seat, taken from the user's usable pack for the resource's
+// type that expires soonest.
+func payWithCredits(ctx context.Context, tx bun.Tx, booking *models.Booking) error {
+	var resourceType string
+	err := tx.NewSelect().
+		Model((*models.Resource)(nil)).
+		Column("type").
+		Where("id = ?", booking.ResourceID).
+		Scan(ctx, &resourceType)
+
+	if err != nil {
+		return fmt.Errorf("failed to fetch resource: %w", err)
+	}
+
+	var pack models.CreditPack
+	err = tx.NewSelect().
+		Model(&pack).
+		Where("user_id = ?", booking.UserID).
+		Where("remaining >= ?", booking.PartySize).
+		Where("expires_at IS NULL OR expires_at > NOW()").
+		Where("cardinality(resource_types) = 0 OR ? = ANY(resource_types)", resourceType).
+		OrderExpr("expires_at ASC NULLS LAST, created_at ASC").
+		Limit(1).
+		For("UPDATE").
+		Scan(ctx)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInsufficientCredits
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch credit packs: %w", err)
+	}
+
+	if err := adjustCredits(ctx, tx, &pack, -booking.PartySize); err != nil {
+		return err
+	}
+
+	_, err = tx.NewUpdate().
+		Model((*models.Booking)(nil)).
+		Set("credit_pack_id = ?", pack.ID).
+		Set("credits_used = ?", booking.PartySize).
+		Where("id = ?", booking.ID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to record credits used: %w", err)
+	}
+	booking.CreditPackID = &pack.ID
+	booking.CreditsUsed = booking.PartySize
+
+	return recordCreditTransaction(ctx, tx, &pack, &booking.ID, "debit", -booking.PartySize, "", &booking.UserID)
+}
+
+// requirePackCovers checks that the pack a booking was paid for with covers
+// the type of the resource it is moving to.
+func requirePackCovers(ctx context.Context, tx bun.Tx, booking *models.Booking, resourceID uuid.UUID) error {
+	covered, err := tx.NewSelect().
+		TableExpr("credit_packs AS p").
+		Join("JOIN resources AS r ON r.id = ?", resourceID).
+		Where("p.id = ?", *booking.CreditPackID).
+		Where("cardinality(p.resource_types) = 0 OR r.type = ANY(p.resource_types)").
+		Exists(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to check credit pack: %w", err)
+	}
+	if !covered {
+		return fmt.Errorf("credit pack does not cover this resource; cancel and rebook instead")
+	}
+
+	return nil
+}
+
+// returnCredits gives the credits a cancelled, rejected or expired booking
+// was paid with back to their pack, unless they were already returned or
+// kept for a late cancellation. Credits returned to an expired pack are
+// written off by the next expiry sweep.
+func returnCredits(ctx context.Context, tx bun.Tx, booking *models.Booking, actorID *uuid.UUID, note string) error {
+	pack, settled, err := lockBookingPack(ctx, tx, booking)
+	if err != nil || pack == nil || settled {
+		return err
+	}
+
+	if err := adjustCredits(ctx, tx, pack, booking.CreditsUsed); err != nil {
+		return err
+	}
+
+	return recordCreditTransaction(ctx, tx, pack, &booking.ID, "refund", booking.CreditsUsed, note, actorID)
+}
+
+// returnPartyCredits gives back the credits for the seats a booking the
+// caller has locked no longer takes after its party shrank to partySize,
+// one per seat.
+func returnPartyCredits(ctx context.Context, tx bun.Tx, booking *models.Booking, partySize int, actorID *uuid.UUID, note string) error {
+	pack, settled, err := lockBookingPack(ctx, tx, booking)
+	if err != nil || pack == nil || settled {
+		return err
+	}
+
+	freed := booking.CreditsUsed - partySize
+	if freed <= 0 {
+		return nil
+	}
+
+	if err := adjustCredits(ctx, tx, pack, freed); err != nil {
+		return err
+	}
+
+	_, err = tx.NewUpdate().
+		Model((*models.Booking)(nil)).
+		Set("credits_used = ?", partySize).
+		Where("id = ?", booking.ID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to record credits used: %w", err)
+	}
+	booking.CreditsUsed = partySize
+
+	return recordCreditTransaction(ctx, tx, pack, &booking.ID, "refund", freed, note, actorID)
+}
+
+// forfeitCredits keeps the credits of a booking cancelled too late for a
+// free cancellation, noting it in the ledger so they are not returned.
+func forfeitCredits(ctx context.Context, tx bun.Tx, booking *models.Booking, actorID *uuid.UUID, note string) error {
+	pack, settled, err := lockBookingPack(ctx, tx, booking)
+	if err != nil || pack == nil || settled {
+		return err
+	}
+
+	return recordCreditTransaction(ctx, tx, pack, &booking.ID, "forfeit", 0, note, actorID)
+}
+
+// lockBookingPack locks the pack a booking was paid from, if any, and
+// reports whether its credits were already returned or forfeited. Credits
+// returned for a reduced party leave the rest outstanding.
+func lockBookingPack(ctx context.Context, tx bun.Tx, booking *models.Booking) (*models.CreditPack, bool, error) {
+	if booking.CreditPackID == nil || booking.CreditsUsed == 0 {
+		return nil, false, nil
+	}
+
+	var pack models.CreditPack
+	err := tx.NewSelect().
+		Model(&pack).
+		Where("id = ?", *booking.CreditPackID).
+		For("UPDATE").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch credit pack: %w", err)
+	}
+
+	var settled bool
+	err = tx.NewSelect().
+		Model((*models.CreditTransaction)(nil)).
+		ColumnExpr("COALESCE(SUM(credits), 0) >= 0 OR COALESCE(BOOL_OR(kind = 'forfeit'), false)").
+		Where("booking_id = ?", booking.ID).
+		Scan(ctx, &settled)
+
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check credit transactions: %w", err)
+	}
+
+	return &pack, settled, nil
+}
+
+func adjustCredits(ctx context.Context, tx bun.Tx, pack *models.CreditPack, credits int) error {
+	_, err := tx.NewUpdate().
+		Model(pack).
+		Set("remaining = remaining + ?", credits).
+		WherePK().
+		Returning("remaining").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update credit pack: %w", err)
+	}
+
+	return nil
+}
+
+func recordCreditTransaction(ctx context.Context, tx bun.Tx, pack *models.CreditPack, bookingID *uuid.UUID, kind string, credits int, note string, actorID *uuid.UUID) error {
+	transaction := &models.CreditTransaction{
+		UserID:    pack.UserID,
+		PackID:    pack.ID,
+		BookingID: bookingID,
+		Kind:      kind,
+		Credits:   credits,
+		Note:      note,
+		ActorID:   actorID,
+	}
+
+	_, err := tx.NewInsert().
+		Model(transaction).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to record credit transaction: %w", err)
+	}
+
+	return nil
+}
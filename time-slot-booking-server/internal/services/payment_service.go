@@ -0,0 +1,516 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"time-slot-booking-server/internal/config"
+	"time-slot-booking-server/internal/db"
+	"time-slot-booking-server/internal/logger"
+	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/payments"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// ErrPaymentsDisabled is returned by payment endpoints when no payment
+// provider is configured.
+var ErrPaymentsDisabled = errors.New("payments are not enabled")
+
+// ErrSimulateUnsupported is returned when asked to simulate a payment with
+// a provider other than the fake one.
+var ErrSimulateUnsupported = errors.New("payments can only be simulated with the fake provider")
+
+// awaitingPayment is the payment status of a priced booking whose payment
+// has not succeeded yet.
+const awaitingPayment = "awaiting_payment"
+
+type PaymentService struct {
+	db       *db.DB
+	provider payments.Provider
+	bookings *BookingService
+}
+
+func NewPaymentService(database *db.DB, provider payments.Provider) *PaymentService {
+	return &PaymentService{db: database, provider: provider, bookings: NewBookingService(database, provider)}
+}
+
+// GetForBooking lists the payments of a booking, newest first. Users see
+// their own bookings' payments, admins any. Client secrets are only
+// returned to the booking's owner.
+func (s *PaymentService) GetForBooking(ctx context.Context, bookingID, userID uuid.UUID, role string) ([]models.Payment, error) {
+	var booking models.Booking
+	query := s.db.NewSelect().
+		Model(&booking).
+		Column("id", "user_id").
+		Where("id = ?", bookingID)
+
+	if role != "admin" {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("booking not found: %w", err)
+	}
+
+	paymentList := make([]models.Payment, 0)
+	err := s.db.NewSelect().
+		Model(&paymentList).
+		Where("booking_id = ?", bookingID).
+		Order("created_at DESC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payments: %w", err)
+	}
+
+	if booking.UserID != userID {
+		for i := range paymentList {
+			paymentList[i].ClientSecret = ""
+		}
+	}
+
+	return paymentList, nil
+}
+
+// HandleWebhook verifies and applies a provider webhook. An authorized
+// payment is captured and confirms its booking, or turns it into an
+// approval request on resources that require one; a failed payment
+// expires the booking. Each event is applied once, however often the
+// provider delivers it, and an event that fails to apply is not recorded,
+// so the provider's retry applies it again.
+func (s *PaymentService) HandleWebhook(ctx context.Context, payload []byte, header http.Header) error {
+	if s.provider == nil {
+		return ErrPaymentsDisabled
+	}
+
+	event, err := s.provider.VerifyWebhook(payload, header)
+	if err != nil {
+		return err
+	}
+
+	// Other components of a bundle whose payment fails are refunded once
+	// the event has been applied
+	var bundle []models.Booking
+
+	err = s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		result, err := tx.NewInsert().
+			Model(&models.PaymentEvent{
+				Provider:         s.provider.Name(),
+				ID:               event.ID,
+				Type:             event.Type,
+				ProviderIntentID: event.IntentID,
+			}).
+			On("CONFLICT DO NOTHING").
+			Exec(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to record payment event: %w", err)
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return nil
+		}
+
+		var bookingID uuid.UUID
+		err = tx.NewSelect().
+			Model((*models.Payment)(nil)).
+			Column("booking_id").
+			Where("provider = ?", s.provider.Name()).
+			Where("provider_intent_id = ?", event.IntentID).
+			Scan(ctx, &bookingID)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Warn().
+				Str("event_id", event.ID).
+				Str("intent_id", event.IntentID).
+				Msg("Ignored payment event for unknown intent")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find payment: %w", err)
+		}
+
+		// Lock the booking before its payment, as the hold sweeper does
+		var booking models.Booking
+		err = tx.NewSelect().
+			Model(&booking).
+			Where("id = ?", bookingID).
+			For("UPDATE").
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("booking not found: %w", err)
+		}
+
+		var payment models.Payment
+		err = tx.NewSelect().
+			Model(&payment).
+			Where("provider = ?", s.provider.Name()).
+			Where("provider_intent_id = ?", event.IntentID).
+			For("UPDATE").
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to lock payment: %w", err)
+		}
+
+		switch event.Type {
+		case payments.EventAuthorized, payments.EventSucceeded:
+			bundle, err = s.settle(ctx, tx, &booking, &payment, event.Type == payments.EventAuthorized)
+			return err
+		case payments.EventFailed:
+			if payment.Status != payments.IntentRequiresPayment || booking.Status != "pending" || booking.PaymentStatus != awaitingPayment {
+				return nil
+			}
+			bundle, err = s.bookings.expirePayment(ctx, tx, &booking, "failed", "payment failed")
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for i := range bundle {
+		s.bookings.issueRefunds(ctx, &bundle[i])
+	}
+
+	return nil
+}
+
+// settle applies a successful payment. The booking may have expired or
+// been cancelled while the customer paid; an authorization for it is then
+// left uncaptured to lapse, and a payment the provider captured by itself
+// is refunded. It returns the other components of the booking's bundle
+// cancelled when it expires.
+func (s *PaymentService) settle(ctx context.Context, tx bun.Tx, booking *models.Booking, payment *models.Payment, needsCapture bool) ([]models.Booking, error) {
+	if payment.Status == payments.IntentSucceeded {
+		return nil, nil
+	}
+
+	var bundle []models.Booking
+	if booking.PaymentStatus == awaitingPayment && booking.HoldExpiresAt != nil && !booking.HoldExpiresAt.After(time.Now()) {
+		var err error
+		bundle, err = s.bookings.expirePayment(ctx, tx, booking, "expired", "payment not received in time")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if booking.Status != "pending" || booking.PaymentStatus != awaitingPayment {
+		if !needsCapture {
+			_, err := s.provider.Refund(ctx, payments.RefundRequest{
+				IntentID:       payment.ProviderIntentID,
+				Amount:         payment.Amount,
+				IdempotencyKey: "late-payment-" + payment.ID.String(),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to refund late payment: %w", err)
+			}
+		}
+
+		logger.Warn().
+			Str("booking_id", booking.ID.String()).
+			Str("intent_id", payment.ProviderIntentID).
+			Str("booking_status", booking.Status).
+			Msg("Released payment received after its booking closed")
+		return bundle, nil
+	}
+
+	if needsCapture {
+		if _, err := s.provider.Capture(ctx, payment.ProviderIntentID); err != nil {
+			return nil, fmt.Errorf("failed to capture payment: %w", err)
+		}
+	}
+
+	_, err := tx.NewUpdate().
+		Model(payment).
+		Set("status = ?", payments.IntentSucceeded).
+		Set("paid_at = NOW()").
+		Set("updated_at = NOW()").
+		WherePK().
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to record payment: %w", err)
+	}
+
+	_, err = tx.NewUpdate().
+		Model((*models.Booking)(nil)).
+		Set("payment_status = 'paid'").
+		Where("id = ?", booking.ID).
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to record payment: %w", err)
+	}
+	booking.PaymentStatus = "paid"
+
+	return nil, confirmHold(ctx, tx, booking, nil, "payment received")
+}
+
+// Simulate settles the pending payment of a booking through the fake
+// provider and applies the webhook it sends, as a customer paying (or
+// failing to) would. Users simulate their own bookings' payments, admins
+// any.
+func (s *PaymentService) Simulate(ctx context.Context, bookingID, userID uuid.UUID, role string, succeed bool) (*models.Payment, error) {
+	fake, ok := s.provider.(*payments.FakeProvider)
+	if !ok {
+		if s.provider == nil {
+			return nil, ErrPaymentsDisabled
+		}
+		return nil, ErrSimulateUnsupported
+	}
+
+	var payment models.Payment
+	query := s.db.NewSelect().
+		Model(&payment).
+		Join("JOIN bookings AS b ON b.id = payment.booking_id").
+		Where("payment.booking_id = ?", bookingID).
+		Where("payment.status = ?", payments.IntentRequiresPayment).
+		Where("payment.provider_intent_id IS NOT NULL")
+
+	if role != "admin" {
+		query = query.Where("b.user_id = ?", userID)
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("no pending payment for booking: %w", err)
+	}
+
+	payload, header, err := fake.Simulate(payment.ProviderIntentID, succeed)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.HandleWebhook(ctx, payload, header); err != nil {
+		return nil, err
+	}
+
+	err = s.db.NewSelect().
+		Model(&payment).
+		WherePK().
+		Scan(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+// requirePayment makes a new priced booking wait for its payment. It
+// records a payment of the total with the service's provider and keeps
+// the booking pending, as a hold expiring after PaymentTimeout, until the
+// payment succeeds. The provider is only asked for an intent once the
+// booking has committed, by openPayments or the payment sweep, so a rolled
+// back booking leaves none behind. Free bookings, and every booking while
+// payments are disabled, are left alone.
+func (s *BookingService) requirePayment(ctx context.Context, tx bun.Tx, booking *models.Booking) error {
+	if s.payments == nil || booking.TotalAmount == nil || booking.TotalAmount.Amount == 0 {
+		return nil
+	}
+
+	expiresAt := time.Now().Add(config.AppConfig.PaymentTimeout)
+	payment := &models.Payment{
+		BookingID: booking.ID,
+		Provider:  s.payments.Name(),
+		Amount:    *booking.TotalAmount,
+		Status:    payments.IntentRequiresPayment,
+		ExpiresAt: expiresAt,
+	}
+
+	_, err := tx.NewInsert().
+		Model(payment).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to record payment: %w", err)
+	}
+
+	_, err = tx.NewUpdate().
+		Model((*models.Booking)(nil)).
+		Set("payment_status = ?", awaitingPayment).
+		Set("hold_expires_at = ?", expiresAt).
+		Where("id = ?", booking.ID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to record payment: %w", err)
+	}
+	booking.PaymentStatus = awaitingPayment
+	booking.HoldExpiresAt = &expiresAt
+	booking.Payment = payment
+
+	if booking.Status == "pending" {
+		return recordTransition(ctx, tx, booking.ID, booking.Status, booking.Status, &booking.UserID, "awaiting payment")
+	}
+
+	return setBookingStatus(ctx, tx, booking, "pending", &booking.UserID, "awaiting payment")
+}
+
+// openPayments creates the provider intents of the payments required by
+// bookings that have just committed, so their owners can pay right away.
+// An intent that cannot be created now is left to the payment sweep.
+func (s *BookingService) openPayments(ctx context.Context, bookings ...*models.Booking) {
+	if s.payments == nil {
+		return
+	}
+
+	for _, booking := range bookings {
+		if booking.Payment == nil || booking.Payment.ProviderIntentID != "" {
+			continue
+		}
+
+		if err := openPayment(ctx, s.db, s.payments, booking.Payment); err != nil {
+			logger.Warn().
+				Err(err).
+				Str("booking_id", booking.ID.String()).
+				Msg("Left payment intent for the payment sweep")
+		}
+	}
+}
+
+// OpenPendingPayments creates the provider intents of outstanding payments
+// that have none yet, because the provider failed or the booking was made
+// inside another transaction. A payment whose intent cannot be created is
+// logged and left for the next sweep, so it does not hold up the others.
+// It returns the number of intents created.
+func (s *BookingService) OpenPendingPayments(ctx context.Context) (int, error) {
+	if s.payments == nil {
+		return 0, nil
+	}
+
+	var pending []models.Payment
+	err := s.db.NewSelect().
+		Model(&pending).
+		Where("provider = ?", s.payments.Name()).
+		Where("provider_intent_id IS NULL").
+		Where("status = ?", payments.IntentRequiresPayment).
+		Where("expires_at > NOW()").
+		Order("created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to find payments without intents: %w", err)
+	}
+
+	opened := 0
+	for i := range pending {
+		if err := openPayment(ctx, s.db, s.payments, &pending[i]); err != nil {
+			logger.Warn().
+				Err(err).
+				Str("booking_id", pending[i].BookingID.String()).
+				Str("payment_id", pending[i].ID.String()).
+				Msg("Failed to open pending payment intent")
+			continue
+		}
+		opened++
+	}
+
+	return opened, nil
+}
+
+// openPayment asks the provider for the intent of a recorded payment and
+// stores it. The idempotency key is the booking's, so a retry, or a sweep
+// racing the request that made the booking, gets the same intent.
+func openPayment(ctx context.Context, idb bun.IDB, provider payments.Provider, payment *models.Payment) error {
+	intent, err := provider.CreateIntent(ctx, payments.IntentRequest{
+		Amount:         payment.Amount,
+		Reference:      payment.BookingID.String(),
+		IdempotencyKey: "booking-" + payment.BookingID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create payment: %w", err)
+	}
+
+	_, err = idb.NewUpdate().
+		Model(payment).
+		Set("provider_intent_id = ?", intent.ID).
+		Set("client_secret = ?", intent.ClientSecret).
+		Set("updated_at = NOW()").
+		WherePK().
+		Where("provider_intent_id IS NULL").
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to record payment intent: %w", err)
+	}
+	payment.ProviderIntentID = intent.ID
+	payment.ClientSecret = intent.ClientSecret
+
+	return nil
+}
+
+// expirePayment expires a booking the caller has locked whose payment did
+// not succeed, like expireUnpaid, and cancels the rest of its bundle with
+// full refunds, since a bundle only makes sense whole. It returns the
+// cancelled bundle components.
+func (s *BookingService) expirePayment(ctx context.Context, tx bun.Tx, booking *models.Booking, paymentStatus, reason string) ([]models.Booking, error) {
+	if err := s.expireUnpaid(ctx, tx, booking, paymentStatus, reason); err != nil {
+		return nil, err
+	}
+
+	if booking.BundleID == nil {
+		return nil, nil
+	}
+
+	return s.cancelBundle(ctx, tx, *booking.BundleID, nil, "bundle component not paid", false)
+}
+
+// expireUnpaid expires a booking the caller has locked whose payment did
+// not succeed, closes the payment with paymentStatus and gives the seats
+// back.
+func (s *BookingService) expireUnpaid(ctx context.Context, tx bun.Tx, booking *models.Booking, paymentStatus, reason string) error {
+	if err := closePayment(ctx, tx, booking, paymentStatus); err != nil {
+		return err
+	}
+
+	if err := setBookingStatus(ctx, tx, booking, "expired", nil, reason); err != nil {
+		return fmt.Errorf("failed to expire booking: %w", err)
+	}
+
+	return s.releaseBookingSeat(ctx, tx, booking)
+}
+
+// closePayment marks the outstanding payment of a booking that is still
+// awaiting one as status, so a later successful payment is not applied to
+// it. Other bookings are left alone.
+func closePayment(ctx context.Context, tx bun.Tx, booking *models.Booking, status string) error {
+	if booking.PaymentStatus != awaitingPayment {
+		return nil
+	}
+
+	_, err := tx.NewUpdate().
+		Model((*models.Payment)(nil)).
+		Set("status = ?", status).
+		Set("updated_at = NOW()").
+		Where("booking_id = ?", booking.ID).
+		Where("status = ?", payments.IntentRequiresPayment).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to close payment: %w", err)
+	}
+
+	_, err = tx.NewUpdate().
+		Model((*models.Booking)(nil)).
+		Set("payment_status = NULL").
+		Where("id = ?", booking.ID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to close payment: %w", err)
+	}
+	booking.PaymentStatus = ""
+
+	return nil
+}
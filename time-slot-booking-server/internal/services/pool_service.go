@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"time-slot-booking-server/internal/db"
+	"time-slot-booking-server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type PoolService struct {
+	db *db.DB
+}
+
+func NewPoolService(database *db.DB) *PoolService {
+	return &PoolService{db: database}
+}
+
+func (s *PoolService) GetAll(ctx context.Context) ([]models.ResourcePool, error) {
+	pools := make([]models.ResourcePool, 0)
+
+	err := s.db.NewSelect().
+		Model(&pools).
+		Order("name ASC").
+		Scan(ctx)
+
+	return pools, err
+}
+
+// GetByID returns a pool with its members in assignment order.
+func (s *PoolService) GetByID(ctx context.Context, id uuid.UUID) (*models.ResourcePool, error) {
+	var pool models.ResourcePool
+	err := s.db.NewSelect().
+		Model(&pool).
+		Where("id = ?", id).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pool.Members = make([]models.ResourcePoolMember, 0)
+	err = s.db.NewSelect().
+		Model(&pool.Members).
+		Where("pool_id = ?", id).
+		Order("priority ASC", "resource_id ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pool members: %w", err)
+	}
+
+	return &pool, nil
+}
+
+func (s *PoolService) Create(ctx context.Context, req *models.CreateResourcePoolRequest) (*models.ResourcePool, error) {
+	strategy := req.Strategy
+	if strategy == "" {
+		strategy = "least_used"
+	}
+	if err := validatePoolStrategy(strategy); err != nil {
+		return nil, err
+	}
+
+	pool := &models.ResourcePool{
+		Name:        req.Name,
+		Description: req.Description,
+		Strategy:    strategy,
+	}
+
+	_, err := s.db.NewInsert().
+		Model(pool).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pool: %w", err)
+	}
+
+	return pool, nil
+}
+
+// Update changes a pool's name, description or strategy.
+func (s *PoolService) Update(ctx context.Context, id uuid.UUID, req *models.CreateResourcePoolRequest) (*models.ResourcePool, error) {
+	query := s.db.NewUpdate().
+		Model((*models.ResourcePool)(nil)).
+		Where("id = ?", id)
+
+	if req.Name != "" {
+		query = query.Set("name = ?", req.Name)
+	}
+	if req.Description != "" {
+		query = query.Set("description = ?", req.Description)
+	}
+	if req.Strategy != "" {
+		if err := validatePoolStrategy(req.Strategy); err != nil {
+			return nil, err
+		}
+		query = query.Set("strategy = ?", req.Strategy)
+	}
+
+	_, err := query.
+		Set("updated_at = NOW()").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update pool: %w", err)
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *PoolService) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.NewDelete().
+		Model((*models.ResourcePool)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	return err
+}
+
+// AddMember puts a resource in a pool, or changes its priority if it is
+// already a member. Pools only hold time slot resources.
+func (s *PoolService) AddMember(ctx context.Context, poolID uuid.UUID, req *models.AddPoolMemberRequest) (*models.ResourcePoolMember, error) {
+	var resource models.Resource
+	err := s.db.NewSelect().
+		Model(&resource).
+		Column("id", "booking_mode").
+		Where("id = ?", req.ResourceID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("resource not found: %w", err)
+	}
+
+	if resource.BookingMode == "interval" {
+		return nil, fmt.Errorf("interval resources cannot be pooled")
+	}
+
+	member := &models.ResourcePoolMember{
+		PoolID:     poolID,
+		ResourceID: req.ResourceID,
+		Priority:   req.Priority,
+	}
+
+	_, err = s.db.NewInsert().
+		Model(member).
+		On("CONFLICT (pool_id, resource_id) DO UPDATE").
+		Set("priority = EXCLUDED.priority").
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to add pool member: %w", err)
+	}
+
+	return member, nil
+}
+
+func (s *PoolService) RemoveMember(ctx context.Context, poolID, resourceID uuid.UUID) error {
+	_, err := s.db.NewDelete().
+		Model((*models.ResourcePoolMember)(nil)).
+		Where("pool_id = ?", poolID).
+		Where("resource_id = ?", resourceID).
+		Exec(ctx)
+
+	return err
+}
+
+func validatePoolStrategy(strategy string) error {
+	switch strategy {
+	case "least_used", "round_robin", "priority":
+		return nil
+	}
+	return fmt.Errorf("strategy must be least_used, round_robin or priority")
+}
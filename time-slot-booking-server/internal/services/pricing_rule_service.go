@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"time-slot-booking-server/internal/db"
+	"time-slot-booking-server/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// ErrPriceChanged is returned when a booking would cost something other
+// than the total the user was quoted.
+var ErrPriceChanged = errors.New("price has changed since it was quoted")
+
+type PricingRuleService struct {
+	db *db.DB
+}
+
+func NewPricingRuleService(database *db.DB) *PricingRuleService {
+	return &PricingRuleService{db: database}
+}
+
+func (s *PricingRuleService) GetAll(ctx context.Context) ([]models.PricingRule, error) {
+	rules := make([]models.PricingRule, 0)
+
+	err := s.db.NewSelect().
+		Model(&rules).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	return rules, err
+}
+
+func (s *PricingRuleService) Create(ctx context.Context, req *models.CreatePricingRuleRequest) (*models.PricingRule, error) {
+	rule := newPricingRule(req)
+	if err := validatePricingRule(rule); err != nil {
+		return nil, err
+	}
+
+	_, err := s.db.NewInsert().
+		Model(rule).
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pricing rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// Update replaces a rule. Bookings already made keep the price they were
+// quoted.
+func (s *PricingRuleService) Update(ctx context.Context, id uuid.UUID, req *models.CreatePricingRuleRequest) (*models.PricingRule, error) {
+	rule := newPricingRule(req)
+	if err := validatePricingRule(rule); err != nil {
+		return nil, err
+	}
+	rule.ID = id
+
+	result, err := s.db.NewUpdate().
+		Model(rule).
+		ExcludeColumn("id", "created_at").
+		Set("updated_at = NOW()").
+		WherePK().
+		Returning("*").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update pricing rule: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, fmt.Errorf("pricing rule not found")
+	}
+
+	return rule, nil
+}
+
+func (s *PricingRuleService) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.NewDelete().
+		Model((*models.PricingRule)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+
+	return err
+}
+
+func newPricingRule(req *models.CreatePricingRuleRequest) *models.PricingRule {
+	return &models.PricingRule{
+		Scope:         req.Scope,
+		ResourceType:  req.ResourceType,
+		ResourceID:    req.ResourceID,
+		Name:          req.Name,
+		Kind:          req.Kind,
+		Weekdays:      req.Weekdays,
+		Open:          req.Open,
+		Close:         req.Close,
+		HoursBefore:   req.HoursBefore,
+		MinPartySize:  req.MinPartySize,
+		AdjustPercent: req.AdjustPercent,
+	}
+}
+
+func validatePricingRule(r *models.PricingRule) error {
+	if err := resolveScope(&r.Scope, r.ResourceType, r.ResourceID); err != nil {
+		return err
+	}
+	return r.Validate()
+}
+
+// Quote prices a booking request without making it: a time slot, or a
+// range on an interval resource, for the party. Passing the quoted total
+// back as quoted_total guarantees the booking is made at that price.
+func (s *BookingService) Quote(ctx context.Context, userID uuid.UUID, req *models.CreateBookingRequest) (*models.PriceQuote, error) {
+	if req.PoolID != nil {
+		return nil, fmt.Errorf("pool bookings are priced once a resource is assigned")
+	}
+
+	partySize, err := partySizeOf(req.PartySize, req.Attendees)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.TimeSlotID != uuid.Nil {
+		var timeSlot models.TimeSlot
+		err := s.db.NewSelect().
+			Model(&timeSlot).
+			Where("id = ?", req.TimeSlotID).
+			Scan(ctx)
+
+		if err != nil || (req.ResourceID != uuid.Nil && timeSlot.ResourceID != req.ResourceID) {
+			return nil, fmt.Errorf("time slot not found")
+		}
+
+		quote, err := priceBooking(ctx, s.db, userID, timeSlot.ResourceID, timeSlot.StartTime, timeSlot.EndTime, timeSlot.Price, partySize)
+		if err != nil {
+			return nil, err
+		}
+		quote.TimeSlotID = &timeSlot.ID
+		return quote, nil
+	}
+
+	if req.StartTime == nil || req.EndTime == nil {
+		return nil, fmt.Errorf("time_slot_id, or start_time and end_time, are required")
+	}
+
+	var resource models.Resource
+	err = s.db.NewSelect().
+		Model(&resource).
+		Where("id = ?", req.ResourceID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("resource not found: %w", err)
+	}
+
+	if resource.BookingMode != "interval" {
+		return nil, fmt.Errorf("resource takes time slot bookings; time_slot_id is required")
+	}
+
+	if err := resource.IntervalRules.Check(*req.StartTime, *req.EndTime, resource.OperatingHours, resource.TimeLocation()); err != nil {
+		return nil, err
+	}
+
+	return priceBooking(ctx, s.db, userID, resource.ID, *req.StartTime, *req.EndTime, resource.IntervalRules.Price(req.EndTime.Sub(*req.StartTime)), partySize)
+}
+
+// priceBooking quotes a booking by the user on the resource from start to
+// end under the pricing rules that apply to the resource now.
+func priceBooking(ctx context.Context, idb bun.IDB, userID, resourceID uuid.UUID, start, end time.Time, base *models.Money, partySize int) (*models.PriceQuote, error) {
+	var resource models.Resource
+	err := idb.NewSelect().
+		Model(&resource).
+		Column("id", "type", "time_zone").
+		Where("id = ?", resourceID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("resource not found: %w", err)
+	}
+
+	rules := make([]models.PricingRule, 0)
+	err = idb.NewSelect().
+		Model(&rules).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.
+				Where("scope = 'global'").
+				WhereOr("scope = 'resource_type' AND resource_type = ?", resource.Type).
+				WhereOr("scope = 'resource' AND resource_id = ?", resource.ID)
+		}).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pricing rules: %w", err)
+	}
+
+	var member bool
+	err = idb.NewSelect().
+		Model((*models.AppUser)(nil)).
+		Column("is_member").
+		Where("id = ?", userID).
+		Scan(ctx, &member)
+
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	quote := models.QuotePrice(rules, &models.PriceRequest{
+		BasePrice: base,
+		StartTime: start,
+		Location:  resource.TimeLocation(),
+		PartySize: partySize,
+		Member:    member,
+		BookedAt:  time.Now(),
+	})
+	quote.ResourceID = resourceID
+	quote.EndTime = end
+
+	return quote, nil
+}
+
+// checkQuotedTotal rejects a booking whose price differs from the total
+// the user accepted, if they gave one. A quote without a currency is taken
+// to be in the booking's currency.
+func checkQuotedTotal(booking *models.Booking, quoted *models.Money) error {
+	if quoted == nil {
+		return nil
+	}
+
+	if booking.TotalAmount == nil {
+		if quoted.Amount != 0 {
+			return ErrPriceChanged
+		}
+		return nil
+	}
+
+	accepted := *quoted
+	if accepted.Currency == "" {
+		accepted.Currency = booking.TotalAmount.Currency
+	}
+	if !booking.TotalAmount.Equal(accepted) {
+		return ErrPriceChanged
+	}
+
+	return nil
+}
+
+// samePrice reports whether two booking totals are equal, treating a
+// missing total as free.
+func samePrice(a, b *models.Money) bool {
+	if a == nil || b == nil {
+		return (a == nil || a.Amount == 0) && (b == nil || b.Amount == 0)
+	}
+	return a.Equal(*b)
+}
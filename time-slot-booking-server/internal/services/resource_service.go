@@ -2,14 +2,17 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
+	"time-slot-booking-server/internal/config"
 	"time-slot-booking-server/internal/db"
 	"time-slot-booking-server/internal/models"
 
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
 )
 
 type ResourceService struct {
@@ -47,13 +50,68 @@ func (s *ResourceService) GetByID(ctx context.Context, id uuid.UUID) (*models.Re
 }
 
 func (s *ResourceService) Create(ctx context.Context, req *models.CreateResourceRequest) (*models.Resource, error) {
+	if req.OperatingHours != nil {
+		if err := req.OperatingHours.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid operating_hours: %w", err)
+		}
+	}
+
+	timeZone := req.TimeZone
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	if err := models.ValidateTimeZone(timeZone); err != nil {
+		return nil, err
+	}
+
+	bookingMode := req.BookingMode
+	if bookingMode == "" {
+		bookingMode = "slots"
+	}
+	if bookingMode != "slots" && bookingMode != "interval" {
+		return nil, fmt.Errorf("booking_mode must be slots or interval")
+	}
+	if req.IntervalRules != nil {
+		if err := req.IntervalRules.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid interval_rules: %w", err)
+		}
+	}
+	if req.CancellationPolicy != nil {
+		if err := req.CancellationPolicy.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid cancellation_policy: %w", err)
+		}
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = config.AppConfig.DefaultCurrency
+	}
+	if err := models.ValidateCurrency(currency); err != nil {
+		return nil, err
+	}
+	if err := priceSchedules(req.OperatingHours, req.IntervalRules, currency); err != nil {
+		return nil, err
+	}
+
+	tags := req.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
 	resource := &models.Resource{
-		Name:           req.Name,
-		Type:           req.Type,
-		Description:    req.Description,
-		Location:       req.Location,
-		Capacity:       req.Capacity,
-		OperatingHours: req.OperatingHours,
+		Name:               req.Name,
+		Type:               req.Type,
+		Description:        req.Description,
+		Location:           req.Location,
+		Tags:               tags,
+		Capacity:           req.Capacity,
+		Currency:           currency,
+		OperatingHours:     req.OperatingHours,
+		TimeZone:           timeZone,
+		RequiresApproval:   req.RequiresApproval,
+		BookingMode:        bookingMode,
+		IntervalRules:      req.IntervalRules,
+		CancellationPolicy: req.CancellationPolicy,
 	}
 
 	_, err := s.db.NewInsert().
@@ -101,14 +159,151 @@ func (s *ResourceService) Update(ctx context.Context, id uuid.UUID, updates map[
 			updateQuery = updateQuery.Set("location = ?", locStr)
 		}
 	}
+	if tags, ok := updates["tags"]; ok {
+		if tagList, ok := tags.([]interface{}); ok {
+			tagStrs := make([]string, 0, len(tagList))
+			for _, tag := range tagList {
+				tagStr, ok := tag.(string)
+				if !ok {
+					return nil, fmt.Errorf("tags must be a list of strings")
+				}
+				tagStrs = append(tagStrs, tagStr)
+			}
+			updateQuery = updateQuery.Set("tags = ?", pgdialect.Array(tagStrs))
+		}
+	}
 	if capacity, ok := updates["capacity"]; ok {
 		if cap, ok := capacity.(float64); ok { // JSON numbers are float64 by default
 			updateQuery = updateQuery.Set("capacity = ?", int(cap))
 		}
 	}
+	if requiresApproval, ok := updates["requires_approval"]; ok {
+		if approvalBool, ok := requiresApproval.(bool); ok {
+			updateQuery = updateQuery.Set("requires_approval = ?", approvalBool)
+		}
+	}
+	currency := resource.Currency
+	if newCurrency, ok := updates["currency"]; ok {
+		if currencyStr, ok := newCurrency.(string); ok && currencyStr != resource.Currency {
+			if err := models.ValidateCurrency(currencyStr); err != nil {
+				return nil, err
+			}
+			// Slots and bookings keep the currency they were priced in
+			priced, err := s.db.NewSelect().
+				Model((*models.TimeSlot)(nil)).
+				Where("resource_id = ?", id).
+				Exists(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check for time slots: %w", err)
+			}
+			if !priced {
+				priced, err = s.db.NewSelect().
+					Model((*models.Booking)(nil)).
+					Where("resource_id = ?", id).
+					Exists(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("failed to check for bookings: %w", err)
+				}
+			}
+			if priced {
+				return nil, fmt.Errorf("currency cannot change once the resource has time slots or bookings")
+			}
+			currency = currencyStr
+			updateQuery = updateQuery.Set("currency = ?", currency)
+		}
+	}
+	if timeZone, ok := updates["time_zone"]; ok {
+		if tzStr, ok := timeZone.(string); ok {
+			if err := models.ValidateTimeZone(tzStr); err != nil {
+				return nil, err
+			}
+			updateQuery = updateQuery.Set("time_zone = ?", tzStr)
+		}
+	}
+	// The schedules the resource ends up with, which must be priced in its
+	// currency
+	hours, rules := resource.OperatingHours, resource.IntervalRules
 	if operatingHours, ok := updates["operating_hours"]; ok {
-		if opHours, ok := operatingHours.(map[string]interface{}); ok {
-			updateQuery = updateQuery.Set("operating_hours = ?", opHours)
+		hours = nil
+		if operatingHours == nil {
+			updateQuery = updateQuery.Set("operating_hours = NULL")
+		} else {
+			// Round-trip through JSON to get the typed schedule
+			raw, err := json.Marshal(operatingHours)
+			if err != nil {
+				return nil, fmt.Errorf("invalid operating_hours: %w", err)
+			}
+			var parsed models.OperatingHours
+			if err := json.Unmarshal(raw, &parsed); err != nil {
+				return nil, fmt.Errorf("invalid operating_hours: %w", err)
+			}
+			if err := parsed.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid operating_hours: %w", err)
+			}
+			if err := priceSchedules(&parsed, nil, currency); err != nil {
+				return nil, err
+			}
+			hours = &parsed
+			raw, _ = json.Marshal(hours)
+			updateQuery = updateQuery.Set("operating_hours = ?", string(raw))
+		}
+	}
+
+	if bookingMode, ok := updates["booking_mode"]; ok {
+		if modeStr, ok := bookingMode.(string); ok {
+			if modeStr != "slots" && modeStr != "interval" {
+				return nil, fmt.Errorf("booking_mode must be slots or interval")
+			}
+			updateQuery = updateQuery.Set("booking_mode = ?", modeStr)
+		}
+	}
+	if intervalRules, ok := updates["interval_rules"]; ok {
+		rules = nil
+		if intervalRules == nil {
+			updateQuery = updateQuery.Set("interval_rules = NULL")
+		} else {
+			raw, err := json.Marshal(intervalRules)
+			if err != nil {
+				return nil, fmt.Errorf("invalid interval_rules: %w", err)
+			}
+			var parsed models.IntervalRules
+			if err := json.Unmarshal(raw, &parsed); err != nil {
+				return nil, fmt.Errorf("invalid interval_rules: %w", err)
+			}
+			if err := parsed.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid interval_rules: %w", err)
+			}
+			if err := priceSchedules(nil, &parsed, currency); err != nil {
+				return nil, err
+			}
+			rules = &parsed
+			raw, _ = json.Marshal(rules)
+			updateQuery = updateQuery.Set("interval_rules = ?", string(raw))
+		}
+	}
+	if cancellationPolicy, ok := updates["cancellation_policy"]; ok {
+		if cancellationPolicy == nil {
+			updateQuery = updateQuery.Set("cancellation_policy = NULL")
+		} else {
+			raw, err := json.Marshal(cancellationPolicy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cancellation_policy: %w", err)
+			}
+			var policy models.CancellationPolicy
+			if err := json.Unmarshal(raw, &policy); err != nil {
+				return nil, fmt.Errorf("invalid cancellation_policy: %w", err)
+			}
+			if err := policy.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid cancellation_policy: %w", err)
+			}
+			raw, _ = json.Marshal(policy)
+			updateQuery = updateQuery.Set("cancellation_policy = ?", string(raw))
+		}
+	}
+
+	if currency != resource.Currency {
+		if err := priceSchedules(hours, rules, currency); err != nil {
+			return nil, fmt.Errorf("%w; update the prices with the currency", err)
 		}
 	}
 
@@ -134,6 +329,22 @@ func (s *ResourceService) Update(ctx context.Context, id uuid.UUID, updates map[
 	return &updatedResource, nil
 }
 
+// priceSchedules puts the default slot price and hourly rate of a resource
+// in its currency, rejecting prices given in another one.
+func priceSchedules(hours *models.OperatingHours, rules *models.IntervalRules, currency string) error {
+	if hours != nil {
+		if err := hours.DefaultPrice.In(currency); err != nil {
+			return fmt.Errorf("invalid operating_hours: default_price: %w", err)
+		}
+	}
+	if rules != nil {
+		if err := rules.HourlyRate.In(currency); err != nil {
+			return fmt.Errorf("invalid interval_rules: hourly_rate: %w", err)
+		}
+	}
+	return nil
+}
+
 func (s *ResourceService) Delete(ctx context.Context, id uuid.UUID) error {
 	_, err := s.db.NewDelete().
 		Model((*models.Resource)(nil)).
@@ -155,6 +366,120 @@ func (s *ResourceService) GetByType(ctx context.Context, resourceType string) ([
 	return resources, err
 }
 
+// ListProviders returns the providers who may review bookings on a resource.
+func (s *ResourceService) ListProviders(ctx context.Context, resourceID uuid.UUID) ([]models.ResourceProvider, error) {
+	providers := make([]models.ResourceProvider, 0)
+
+	err := s.db.NewSelect().
+		Model(&providers).
+		Where("resource_id = ?", resourceID).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	return providers, err
+}
+
+// AddProvider lets a provider or admin user review bookings on a resource.
+func (s *ResourceService) AddProvider(ctx context.Context, resourceID, userID uuid.UUID) (*models.ResourceProvider, error) {
+	var user models.AppUser
+	err := s.db.NewSelect().
+		Model(&user).
+		Column("id", "role").
+		Where("id = ?", userID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	if user.Role != "provider" && user.Role != "admin" {
+		return nil, fmt.Errorf("user must have the provider or admin role")
+	}
+
+	provider := &models.ResourceProvider{
+		ResourceID: resourceID,
+		UserID:     userID,
+	}
+
+	_, err = s.db.NewInsert().
+		Model(provider).
+		On("CONFLICT (resource_id, user_id) DO NOTHING").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to add provider: %w", err)
+	}
+
+	return provider, nil
+}
+
+func (s *ResourceService) RemoveProvider(ctx context.Context, resourceID, userID uuid.UUID) error {
+	_, err := s.db.NewDelete().
+		Model((*models.ResourceProvider)(nil)).
+		Where("resource_id = ?", resourceID).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+
+	return err
+}
+
+// ListDependencies returns the pools a resource needs a member of whenever
+// it is booked.
+func (s *ResourceService) ListDependencies(ctx context.Context, resourceID uuid.UUID) ([]models.ResourceDependency, error) {
+	dependencies := make([]models.ResourceDependency, 0)
+
+	err := s.db.NewSelect().
+		Model(&dependencies).
+		Where("resource_id = ?", resourceID).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	return dependencies, err
+}
+
+// AddDependency requires bookings of the resource to include a member of
+// the pool. A resource cannot depend on a pool it belongs to.
+func (s *ResourceService) AddDependency(ctx context.Context, resourceID, poolID uuid.UUID) (*models.ResourceDependency, error) {
+	member, err := s.db.NewSelect().
+		Model((*models.ResourcePoolMember)(nil)).
+		Where("pool_id = ?", poolID).
+		Where("resource_id = ?", resourceID).
+		Exists(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to check pool members: %w", err)
+	}
+	if member {
+		return nil, fmt.Errorf("resource cannot depend on its own pool")
+	}
+
+	dependency := &models.ResourceDependency{
+		ResourceID: resourceID,
+		PoolID:     poolID,
+	}
+
+	_, err = s.db.NewInsert().
+		Model(dependency).
+		On("CONFLICT (resource_id, pool_id) DO NOTHING").
+		Exec(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to add dependency: %w", err)
+	}
+
+	return dependency, nil
+}
+
+func (s *ResourceService) RemoveDependency(ctx context.Context, resourceID, poolID uuid.UUID) error {
+	_, err := s.db.NewDelete().
+		Model((*models.ResourceDependency)(nil)).
+		Where("resource_id = ?", resourceID).
+		Where("pool_id = ?", poolID).
+		Exec(ctx)
+
+	return err
+}
+
 type TimeSlotService struct {
 	db *db.DB
 }
@@ -163,37 +488,94 @@ func NewTimeSlotService(database *db.DB) *TimeSlotService {
 	return &TimeSlotService{db: database}
 }
 
-func (s *TimeSlotService) GetAvailable(ctx context.Context, resourceID uuid.UUID, startDate, endDate time.Time) ([]models.TimeSlot, error) {
+func (s *TimeSlotService) GetAvailable(ctx context.Context, resourceID uuid.UUID, startDate, endDate time.Time, partySize int) ([]models.TimeSlot, error) {
 	var timeSlots []models.TimeSlot
 
 	query := s.db.NewSelect().
 		Model(&timeSlots).
 		Where("resource_id = ?", resourceID).
-		Where("is_available = ?", true).
 		Where("start_time >= ?", startDate).
 		Where("end_time <= ?", endDate).
 		Order("start_time ASC")
 
-	// Add capacity check
-	query = query.Where("(capacity - COALESCE((SELECT COUNT(*) FROM bookings b WHERE b.time_slot_id = time_slot.id AND b.status IN ('pending', 'confirmed')), 0)) > 0")
-
-	err := query.Scan(ctx)
+	err := bookable(query, "time_slot", partySize).Scan(ctx)
 
 	return timeSlots, err
 }
 
-func (s *TimeSlotService) Create(ctx context.Context, resourceID uuid.UUID, startTime, endTime time.Time, capacity int, price *float64) (*models.TimeSlot, error) {
+// NextAvailable returns the earliest time slots of a resource with seats
+// for the party that start after the given time, applying the same rules as
+// GetAvailable and skipping slots the booking rules' notice and advance
+// windows would refuse.
+func (s *TimeSlotService) NextAvailable(ctx context.Context, resourceID uuid.UUID, after time.Time, partySize, limit int) ([]models.TimeSlot, error) {
+	var resource models.Resource
+	err := s.db.NewSelect().
+		Model(&resource).
+		Column("id", "type", "booking_mode").
+		Where("id = ?", resourceID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch resource: %w", err)
+	}
+
+	if resource.BookingMode == "interval" {
+		return nil, fmt.Errorf("resource takes interval bookings and has no time slots")
+	}
+
+	rules, err := applicableRules(ctx, s.db, &resource)
+	if err != nil {
+		return nil, err
+	}
+	earliest, latest := bookingWindow(rules, time.Now())
+
+	timeSlots := make([]models.TimeSlot, 0, limit)
+
+	query := s.db.NewSelect().
+		Model(&timeSlots).
+		Where("resource_id = ?", resourceID).
+		Where("start_time >= ?", maxTime(after, earliest)).
+		Order("start_time ASC").
+		Limit(limit)
+
+	if latest != nil {
+		query = query.Where("start_time <= ?", *latest)
+	}
+
+	if err := bookable(query, "time_slot", partySize).Scan(ctx); err != nil {
+		return nil, fmt.Errorf("failed to find available slots: %w", err)
+	}
+
+	return timeSlots, nil
+}
+
+// bookable narrows a time slot query, with slots under alias, to slots that
+// can take a booking of the given number of seats now: open, not yet
+// started, with enough free seats and not closed by a blackout period.
+// booked_count includes seats taken by active holds and waitlist offers.
+func bookable(query *bun.SelectQuery, alias string, seats int) *bun.SelectQuery {
+	return query.
+		Where(alias+".is_available").
+		Where(alias+".start_time > NOW()").
+		Where(alias+".booked_count + ? <= "+alias+".capacity", seats).
+		Where(notBlackedOut(alias))
+}
+
+func (s *TimeSlotService) Create(ctx context.Context, resourceID uuid.UUID, startTime, endTime time.Time, capacity int, price *models.Money) (*models.TimeSlot, error) {
+	var resource models.Resource
+	err := s.db.NewSelect().
+		Model(&resource).
+		Where("id = ?", resourceID).
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch resource: %w", err)
+	}
 	if capacity <= 0 {
-		var resource models.Resource
-		err := s.db.NewSelect().
-			Model(&resource).
-			Where("id = ?", resourceID).
-			Scan(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch resource: %w", err)
-		}
 		capacity = resource.Capacity
 	}
+	if err := price.In(resource.Currency); err != nil {
+		return nil, fmt.Errorf("invalid price: %w", err)
+	}
 
 	timeSlot := &models.TimeSlot{
 		ResourceID:  resourceID,
@@ -204,32 +586,39 @@ func (s *TimeSlotService) Create(ctx context.Context, resourceID uuid.UUID, star
 		Price:       price,
 	}
 
-	_, err := s.db.NewInsert().
+	_, err = s.db.NewInsert().
 		Model(timeSlot).
 		Exec(ctx)
 
 	return timeSlot, err
 }
 
-func (s *TimeSlotService) CreateBulk(ctx context.Context, resourceID uuid.UUID, baseStartTime time.Time, duration time.Duration, increment time.Duration, count int, capacity int, price *float64) ([]models.TimeSlot, error) {
+func (s *TimeSlotService) CreateBulk(ctx context.Context, resourceID uuid.UUID, baseStartTime time.Time, duration time.Duration, increment time.Duration, count int, capacity int, price *models.Money) ([]models.TimeSlot, error) {
+	var resource models.Resource
+	err := s.db.NewSelect().
+		Model(&resource).
+		Where("id = ?", resourceID).
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch resource: %w", err)
+	}
 	if capacity <= 0 {
-		var resource models.Resource
-		err := s.db.NewSelect().
-			Model(&resource).
-			Where("id = ?", resourceID).
-			Scan(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch resource: %w", err)
-		}
 		capacity = resource.Capacity
 	}
+	if err := price.In(resource.Currency); err != nil {
+		return nil, fmt.Errorf("invalid price: %w", err)
+	}
+
+	// Step on the resource's wall clock so a daily increment keeps the same
+	// local start time across daylight saving changes
+	base := baseStartTime.In(resource.TimeLocation())
 
 	var timeSlots []models.TimeSlot
 
 	// Use a transaction to ensure data consistency
-	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+	err = s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
 		for i := 0; i < count; i++ {
-			startTime := baseStartTime.Add(time.Duration(i) * increment)
+			startTime := addWallClock(base, time.Duration(i)*increment)
 			endTime := startTime.Add(duration)
 
 			timeSlot := &models.TimeSlot{
@@ -258,6 +647,22 @@ func (s *TimeSlotService) CreateBulk(ctx context.Context, resourceID uuid.UUID,
 	return timeSlots, err
 }
 
+// ResourceLocation returns the time zone of a resource.
+func (s *TimeSlotService) ResourceLocation(ctx context.Context, resourceID uuid.UUID) (*time.Location, error) {
+	var resource models.Resource
+	err := s.db.NewSelect().
+		Model(&resource).
+		Column("id", "time_zone").
+		Where("id = ?", resourceID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch resource: %w", err)
+	}
+
+	return resource.TimeLocation(), nil
+}
+
 func (s *TimeSlotService) Delete(ctx context.Context, id uuid.UUID) error {
 	_, err := s.db.NewDelete().
 		Model((*models.TimeSlot)(nil)).
@@ -267,12 +672,126 @@ func (s *TimeSlotService) Delete(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+// UpdateAvailability disables or re-enables a time slot. A re-enabled slot
+// stays unavailable while it is full.
 func (s *TimeSlotService) UpdateAvailability(ctx context.Context, id uuid.UUID, isAvailable bool) error {
 	_, err := s.db.NewUpdate().
 		Model((*models.TimeSlot)(nil)).
-		Set("is_available = ?", isAvailable).
+		Set("is_disabled = ?", !isAvailable).
+		Set("is_available = (? AND booked_count < capacity)", isAvailable).
 		Where("id = ?", id).
 		Exec(ctx)
 
 	return err
 }
+
+// ExportCatalog returns every resource together with its time slots.
+func (s *ResourceService) ExportCatalog(ctx context.Context) (*models.Catalog, error) {
+	catalog := &models.Catalog{
+		Resources: make([]models.Resource, 0),
+		TimeSlots: make([]models.TimeSlot, 0),
+	}
+
+	err := s.db.NewSelect().
+		Model(&catalog.Resources).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to export resources: %w", err)
+	}
+
+	err = s.db.NewSelect().
+		Model(&catalog.TimeSlots).
+		Order("resource_id ASC", "start_time ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to export time slots: %w", err)
+	}
+
+	return catalog, nil
+}
+
+// ImportCatalog inserts the resources and time slots of a catalog, keeping
+// their IDs and skipping any that already exist. Bookings are not part of a
+// catalog, so imported slots start with no seats taken. Slot prices must be
+// in the currency of their resource.
+func (s *ResourceService) ImportCatalog(ctx context.Context, catalog *models.Catalog) (resources int, timeSlots int, err error) {
+	for i := range catalog.Resources {
+		if catalog.Resources[i].Currency == "" {
+			catalog.Resources[i].Currency = config.AppConfig.DefaultCurrency
+		}
+		if err := models.ValidateCurrency(catalog.Resources[i].Currency); err != nil {
+			return 0, 0, fmt.Errorf("resource %s: %w", catalog.Resources[i].ID, err)
+		}
+	}
+
+	err = s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if len(catalog.Resources) > 0 {
+			res, err := tx.NewInsert().
+				Model(&catalog.Resources).
+				On("CONFLICT (id) DO NOTHING").
+				Exec(ctx)
+
+			if err != nil {
+				return fmt.Errorf("failed to import resources: %w", err)
+			}
+
+			n, _ := res.RowsAffected()
+			resources = int(n)
+		}
+
+		if len(catalog.TimeSlots) > 0 {
+			resourceIDs := make([]uuid.UUID, 0, len(catalog.TimeSlots))
+			for _, slot := range catalog.TimeSlots {
+				resourceIDs = append(resourceIDs, slot.ResourceID)
+			}
+
+			var owners []models.Resource
+			err := tx.NewSelect().
+				Model(&owners).
+				Column("id", "currency").
+				Where("id IN (?)", bun.In(resourceIDs)).
+				Scan(ctx)
+
+			if err != nil {
+				return fmt.Errorf("failed to fetch resource currencies: %w", err)
+			}
+
+			currencies := make(map[uuid.UUID]string, len(owners))
+			for _, owner := range owners {
+				currencies[owner.ID] = owner.Currency
+			}
+
+			for i := range catalog.TimeSlots {
+				slot := &catalog.TimeSlots[i]
+				currency, ok := currencies[slot.ResourceID]
+				if !ok {
+					return fmt.Errorf("time slot %s: resource %s not found", slot.ID, slot.ResourceID)
+				}
+				if err := slot.Price.In(currency); err != nil {
+					return fmt.Errorf("time slot %s: %w", slot.ID, err)
+				}
+				slot.BookedCount = 0
+				slot.IsAvailable = !slot.IsDisabled
+			}
+
+			res, err := tx.NewInsert().
+				Model(&catalog.TimeSlots).
+				On("CONFLICT (id) DO NOTHING").
+				Exec(ctx)
+
+			if err != nil {
+				return fmt.Errorf("failed to import time slots: %w", err)
+			}
+
+			n, _ := res.RowsAffected()
+			timeSlots = int(n)
+		}
+
+		return nil
+	})
+
+	return resources, timeSlots, err
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"time-slot-booking-server/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// Generate materializes time slots from a resource's operating hours for the
+// given number of days starting today. Candidate slots that overlap an
+// existing slot of the resource are skipped, so running it repeatedly only
+// fills in what is missing. Slots inside blackout periods are not created.
+// It returns the slots that were created.
+func (s *TimeSlotService) Generate(ctx context.Context, resourceID uuid.UUID, days int) ([]models.TimeSlot, error) {
+	var resource models.Resource
+	err := s.db.NewSelect().
+		Model(&resource).
+		Where("id = ?", resourceID).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch resource: %w", err)
+	}
+
+	if resource.BookingMode == "interval" {
+		return nil, fmt.Errorf("resource takes interval bookings and has no time slots")
+	}
+
+	if !resource.OperatingHours.GeneratesSlots() {
+		return nil, fmt.Errorf("resource has no slot schedule: operating_hours.slot_minutes is not set")
+	}
+
+	return s.generateForResource(ctx, &resource, time.Now(), days)
+}
+
+// GenerateAll runs Generate for every resource with a slot schedule and
+// returns the total number of slots created.
+func (s *TimeSlotService) GenerateAll(ctx context.Context, days int) (int, error) {
+	var resources []models.Resource
+	err := s.db.NewSelect().
+		Model(&resources).
+		Where("operating_hours IS NOT NULL").
+		Where("booking_mode = ?", "slots").
+		Scan(ctx)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch resources: %w", err)
+	}
+
+	created := 0
+	now := time.Now()
+	for i := range resources {
+		if !resources[i].OperatingHours.GeneratesSlots() {
+			continue
+		}
+
+		slots, err := s.generateForResource(ctx, &resources[i], now, days)
+		if err != nil {
+			return created, fmt.Errorf("failed to generate slots for resource %s: %w", resources[i].ID, err)
+		}
+		created += len(slots)
+	}
+
+	return created, nil
+}
+
+func (s *TimeSlotService) generateForResource(ctx context.Context, resource *models.Resource, from time.Time, days int) ([]models.TimeSlot, error) {
+	candidates, err := scheduleSlots(resource, from, days)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	windowStart, windowEnd := candidates[0].StartTime, candidates[0].EndTime
+	for _, c := range candidates {
+		if c.StartTime.Before(windowStart) {
+			windowStart = c.StartTime
+		}
+		if c.EndTime.After(windowEnd) {
+			windowEnd = c.EndTime
+		}
+	}
+
+	var created []models.TimeSlot
+	err = s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// Serialize generation per resource so concurrent runs cannot both
+		// decide the same slot is missing
+		_, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext(?))", resource.ID.String())
+		if err != nil {
+			return fmt.Errorf("failed to lock resource: %w", err)
+		}
+
+		var existing []models.TimeSlot
+		err = tx.NewSelect().
+			Model(&existing).
+			Column("start_time", "end_time").
+			Where("resource_id = ?", resource.ID).
+			Where("end_time > ?", windowStart).
+			Where("start_time < ?", windowEnd).
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to fetch existing time slots: %w", err)
+		}
+
+		blackouts, err := resourceBlackouts(ctx, tx, resource, windowStart, windowEnd)
+		if err != nil {
+			return fmt.Errorf("failed to fetch blackout periods: %w", err)
+		}
+
+		for _, candidate := range candidates {
+			if !overlapsAny(candidate, existing) && !overlapsBlackout(candidate, blackouts) {
+				created = append(created, candidate)
+			}
+		}
+
+		if len(created) == 0 {
+			return nil
+		}
+
+		_, err = tx.NewInsert().
+			Model(&created).
+			Exec(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to create time slots: %w", err)
+		}
+
+		return nil
+	})
+
+	return created, err
+}
+
+// scheduleSlots lays out the slots a resource's schedule defines for days
+// starting at from's calendar date, leaving out any that start before from.
+// Opening hours are read on the resource's local wall clock.
+func scheduleSlots(resource *models.Resource, from time.Time, days int) ([]models.TimeSlot, error) {
+	from = from.In(resource.TimeLocation())
+
+	hours := resource.OperatingHours
+	if err := hours.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid operating_hours: %w", err)
+	}
+
+	slotLength := time.Duration(hours.SlotMinutes) * time.Minute
+	step := slotLength + time.Duration(hours.BufferMinutes)*time.Minute
+
+	capacity := hours.DefaultCapacity
+	if capacity <= 0 {
+		capacity = resource.Capacity
+	}
+
+	var slots []models.TimeSlot
+	for i := 0; i < days; i++ {
+		day := from.AddDate(0, 0, i)
+
+		for _, interval := range hours.IntervalsFor(day.Weekday()) {
+			open, close, err := interval.Bounds()
+			if err != nil {
+				return nil, err
+			}
+
+			for offset := open; offset+slotLength <= close; offset += step {
+				start := atClock(day, offset)
+				if start.Before(from) || !existsOnClock(start, offset) {
+					continue
+				}
+
+				slots = append(slots, models.TimeSlot{
+					ResourceID:  resource.ID,
+					StartTime:   start,
+					EndTime:     start.Add(slotLength),
+					Capacity:    capacity,
+					IsAvailable: true,
+					Price:       hours.DefaultPrice,
+				})
+			}
+		}
+	}
+
+	return slots, nil
+}
+
+// atClock returns the time on day's date in day's location whose wall clock
+// reads offset past midnight. On daylight saving days this differs from
+// adding offset to midnight.
+func atClock(day time.Time, offset time.Duration) time.Time {
+	y, m, d := day.Date()
+	return time.Date(y, m, d, 0, 0, 0, int(offset), day.Location())
+}
+
+// addWallClock advances t's wall-clock reading by d in t's location, so a
+// 24h step lands on the same local time even across daylight saving changes.
+// A reading skipped by a daylight saving jump is moved past the jump.
+func addWallClock(t time.Time, d time.Duration) time.Time {
+	y, m, day := t.Date()
+	h, min, sec := t.Clock()
+	want := time.Date(y, m, day, h, min, sec, t.Nanosecond()+int(d), time.UTC)
+
+	got := time.Date(want.Year(), want.Month(), want.Day(), want.Hour(), want.Minute(), want.Second(), want.Nanosecond(), t.Location())
+	read := time.Date(got.Year(), got.Month(), got.Day(), got.Hour(), got.Minute(), got.Second(), got.Nanosecond(), time.UTC)
+
+	// time.Date reads a skipped time with the offset after the jump, which
+	// lands before it
+	return got.Add(want.Sub(read))
+}
+
+// existsOnClock reports whether t's wall clock reads offset past midnight.
+// It is false for local times skipped by a daylight saving jump, which
+// time.Date silently moves to another hour.
+func existsOnClock(t time.Time, offset time.Duration) bool {
+	h, min, _ := t.Clock()
+	return time.Duration(h)*time.Hour+time.Duration(min)*time.Minute == offset%(24*time.Hour)
+}
+
+func overlapsAny(slot models.TimeSlot, existing []models.TimeSlot) bool {
+	for _, e := range existing {
+		if slot.StartTime.Before(e.EndTime) && e.StartTime.Before(slot.EndTime) {
+			return true
+		}
+	}
+	return false
+}
+
+func overlapsBlackout(slot models.TimeSlot, blackouts []models.Blackout) bool {
+	for _, b := range blackouts {
+		if slot.StartTime.Before(b.EndsAt) && b.StartsAt.Before(slot.EndTime) {
+			return true
+		}
+	}
+	return false
+}
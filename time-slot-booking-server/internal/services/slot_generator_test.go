@@ -0,0 +1,185 @@
+package services
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"time-slot-booking-server/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// New York springs forward at 02:00 on 2025-03-09 and falls back at 02:00
+// on 2025-11-02.
+var (
+	newYork     = mustLoadLocation("America/New_York")
+	springDay   = time.Date(2025, 3, 9, 0, 0, 0, 0, newYork)
+	fallBackDay = time.Date(2025, 11, 2, 0, 0, 0, 0, newYork)
+)
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+func utc(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestScheduleSlotsAcrossDST(t *testing.T) {
+	tests := []struct {
+		name string
+		day  time.Time
+		want []time.Time
+	}{
+		{
+			name: "spring forward skips the missing hour",
+			day:  springDay,
+			want: []time.Time{
+				utc(2025, 3, 9, 5, 0), // 00:00 EST
+				utc(2025, 3, 9, 6, 0), // 01:00 EST
+				utc(2025, 3, 9, 7, 0), // 03:00 EDT
+				utc(2025, 3, 9, 8, 0), // 04:00 EDT
+			},
+		},
+		{
+			name: "fall back keeps one slot per wall-clock hour",
+			day:  fallBackDay,
+			want: []time.Time{
+				utc(2025, 11, 2, 4, 0), // 00:00 EDT
+				utc(2025, 11, 2, 5, 0), // 01:00 EDT
+				utc(2025, 11, 2, 7, 0), // 02:00 EST
+				utc(2025, 11, 2, 8, 0), // 03:00 EST
+				utc(2025, 11, 2, 9, 0), // 04:00 EST
+			},
+		},
+	}
+
+	resource := &models.Resource{
+		ID:       uuid.New(),
+		Capacity: 1,
+		TimeZone: "America/New_York",
+		OperatingHours: &models.OperatingHours{
+			Open:        "00:00",
+			Close:       "05:00",
+			SlotMinutes: 60,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots, err := scheduleSlots(resource, tt.day, 1)
+			if err != nil {
+				t.Fatalf("scheduleSlots: %v", err)
+			}
+
+			if len(slots) != len(tt.want) {
+				t.Fatalf("got %d slots, want %d: %v", len(slots), len(tt.want), slots)
+			}
+
+			for i, slot := range slots {
+				if !slot.StartTime.Equal(tt.want[i]) {
+					t.Errorf("slot %d starts at %s, want %s", i, slot.StartTime.UTC(), tt.want[i])
+				}
+				if got := slot.EndTime.Sub(slot.StartTime); got != time.Hour {
+					t.Errorf("slot %d lasts %s, want 1h", i, got)
+				}
+			}
+		})
+	}
+}
+
+func TestAtClockAcrossDST(t *testing.T) {
+	tests := []struct {
+		name   string
+		day    time.Time
+		offset time.Duration
+		want   time.Time
+	}{
+		{"spring forward before the jump", springDay, time.Hour, utc(2025, 3, 9, 6, 0)},
+		{"spring forward after the jump", springDay, 3 * time.Hour, utc(2025, 3, 9, 7, 0)},
+		{"spring forward late evening", springDay, 23 * time.Hour, utc(2025, 3, 10, 3, 0)},
+		{"fall back after the repeat", fallBackDay, 3 * time.Hour, utc(2025, 11, 2, 8, 0)},
+		{"fall back late evening", fallBackDay, 23 * time.Hour, utc(2025, 11, 3, 4, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := atClock(tt.day, tt.offset)
+			if !got.Equal(tt.want) {
+				t.Errorf("atClock(%s, %s) = %s, want %s", tt.day.Format(time.DateOnly), tt.offset, got.UTC(), tt.want)
+			}
+		})
+	}
+}
+
+func TestExistsOnClockAcrossDST(t *testing.T) {
+	tests := []struct {
+		name   string
+		day    time.Time
+		offset time.Duration
+		want   bool
+	}{
+		{"spring forward before the jump", springDay, time.Hour + 30*time.Minute, true},
+		{"spring forward in the gap", springDay, 2 * time.Hour, false},
+		{"spring forward end of the gap", springDay, 2*time.Hour + 59*time.Minute, false},
+		{"spring forward after the jump", springDay, 3 * time.Hour, true},
+		{"fall back repeated hour", fallBackDay, time.Hour + 30*time.Minute, true},
+		{"fall back after the repeat", fallBackDay, 2 * time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := existsOnClock(atClock(tt.day, tt.offset), tt.offset); got != tt.want {
+				t.Errorf("existsOnClock at %s on %s = %v, want %v", tt.offset, tt.day.Format(time.DateOnly), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddWallClockAcrossDST(t *testing.T) {
+	tests := []struct {
+		name string
+		base time.Time
+		d    time.Duration
+		want time.Time
+	}{
+		{
+			name: "day step into spring forward keeps the local time",
+			base: time.Date(2025, 3, 8, 9, 0, 0, 0, newYork),
+			d:    24 * time.Hour,
+			want: utc(2025, 3, 9, 13, 0),
+		},
+		{
+			name: "week step across spring forward keeps the local time",
+			base: time.Date(2025, 3, 5, 18, 0, 0, 0, newYork),
+			d:    7 * 24 * time.Hour,
+			want: utc(2025, 3, 12, 22, 0),
+		},
+		{
+			name: "day step into fall back keeps the local time",
+			base: time.Date(2025, 11, 1, 9, 0, 0, 0, newYork),
+			d:    24 * time.Hour,
+			want: utc(2025, 11, 2, 14, 0),
+		},
+		{
+			name: "step into the spring forward gap moves past it",
+			base: time.Date(2025, 3, 9, 1, 30, 0, 0, newYork),
+			d:    time.Hour,
+			want: utc(2025, 3, 9, 7, 30),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := addWallClock(tt.base, tt.d)
+			if !got.Equal(tt.want) {
+				t.Errorf("addWallClock(%s, %s) = %s, want %s", tt.base, tt.d, got.UTC(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"time-slot-booking-server/internal/auth"
+	"time-slot-booking-server/internal/db"
+	"time-slot-booking-server/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// validRoles are the roles an AppUser may hold.
+var validRoles = map[string]bool{
+	"admin":    true,
+	"provider": true,
+	"customer": true,
+}
+
+type UserService struct {
+	db *db.DB
+}
+
+func NewUserService(database *db.DB) *UserService {
+	return &UserService{db: database}
+}
+
+// GetAll returns every user with personal fields decrypted.
+func (s *UserService) GetAll(ctx context.Context) ([]models.DecryptedAppUser, error) {
+	var users []models.AppUser
+
+	err := s.db.NewSelect().
+		Model(&users).
+		Order("created_at ASC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.DecryptedAppUser, 0, len(users))
+	for i := range users {
+		decrypted, err := decryptAppUser(&users[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *decrypted)
+	}
+
+	return result, nil
+}
+
+// GetByEmail finds a user by email. Emails are stored encrypted with a random
+// nonce, so every row has to be decrypted and compared.
+func (s *UserService) GetByEmail(ctx context.Context, email string) (*models.DecryptedAppUser, error) {
+	users, err := s.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range users {
+		if strings.EqualFold(users[i].Email, email) {
+			return &users[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("user with email %s not found", email)
+}
+
+func (s *UserService) UpdateRole(ctx context.Context, userID uuid.UUID, role string) error {
+	if !validRoles[role] {
+		return fmt.Errorf("invalid role %q: must be one of admin, provider, customer", role)
+	}
+
+	_, err := s.db.NewUpdate().
+		Model((*models.AppUser)(nil)).
+		Set("role = ?", role).
+		Set("updated_at = NOW()").
+		Where("id = ?", userID).
+		Exec(ctx)
+
+	return err
+}
+
+// SetMember grants or revokes membership, which member pricing rules
+// discount.
+func (s *UserService) SetMember(ctx context.Context, userID uuid.UUID, member bool) error {
+	_, err := s.db.NewUpdate().
+		Model((*models.AppUser)(nil)).
+		Set("is_member = ?", member).
+		Set("updated_at = NOW()").
+		Where("id = ?", userID).
+		Exec(ctx)
+
+	return err
+}
+
+// RotateEncryptionKey re-encrypts every encrypted AppUser column from oldKey
+// to newKey in a single transaction and returns the number of users updated.
+func (s *UserService) RotateEncryptionKey(ctx context.Context, oldKey, newKey []byte) (int, error) {
+	var rotated int
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var users []models.AppUser
+		err := tx.NewSelect().
+			Model(&users).
+			For("UPDATE").
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to load users: %w", err)
+		}
+
+		for i := range users {
+			user := &users[i]
+			fields := []*[]byte{&user.Email, &user.Name, &user.ProviderUserID, &user.AccessToken, &user.RefreshToken}
+
+			for _, field := range fields {
+				if len(*field) == 0 {
+					continue
+				}
+
+				plaintext, err := auth.DecryptWithKey(oldKey, *field)
+				if err != nil {
+					return fmt.Errorf("failed to decrypt user %s with the old key: %w", user.ID, err)
+				}
+
+				ciphertext, err := auth.EncryptWithKey(newKey, plaintext)
+				if err != nil {
+					return fmt.Errorf("failed to encrypt user %s with the new key: %w", user.ID, err)
+				}
+
+				*field = ciphertext
+			}
+
+			_, err = tx.NewUpdate().
+				Model(user).
+				Column("email", "name", "provider_user_id", "access_token", "refresh_token").
+				WherePK().
+				Exec(ctx)
+
+			if err != nil {
+				return fmt.Errorf("failed to update user %s: %w", user.ID, err)
+			}
+
+			rotated++
+		}
+
+		return nil
+	})
+
+	return rotated, err
+}
+
+func decryptAppUser(user *models.AppUser) (*models.DecryptedAppUser, error) {
+	email, err := auth.Decrypt(user.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt email of user %s: %w", user.ID, err)
+	}
+	name, err := auth.Decrypt(user.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt name of user %s: %w", user.ID, err)
+	}
+	providerUserID, err := auth.Decrypt(user.ProviderUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt provider_user_id of user %s: %w", user.ID, err)
+	}
+
+	return &models.DecryptedAppUser{
+		ID:             user.ID,
+		Email:          string(email),
+		Name:           string(name),
+		Provider:       user.Provider,
+		ProviderUserID: string(providerUserID),
+		Role:           user.Role,
+		IsMember:       user.IsMember,
+	}, nil
+}
@@ -0,0 +1,424 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"time-slot-booking-server/internal/config"
+	"time-slot-booking-server/internal/db"
+	"time-slot-booking-server/internal/logger"
+	"time-slot-booking-server/internal/models"
+	"time-slot-booking-server/internal/payments"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type WaitlistService struct {
+	db       *db.DB
+	bookings *BookingService
+}
+
+func NewWaitlistService(database *db.DB, provider payments.Provider) *WaitlistService {
+	return &WaitlistService{db: database, bookings: NewBookingService(database, provider)}
+}
+
+// Join queues the user for a full time slot. Auto-book entries are booked as
+// soon as a seat frees up; the others receive a time-limited offer.
+func (s *WaitlistService) Join(ctx context.Context, userID uuid.UUID, req *models.JoinWaitlistRequest) (*models.WaitlistEntry, error) {
+	entry := &models.WaitlistEntry{
+		TimeSlotID: req.TimeSlotID,
+		UserID:     userID,
+		Status:     "waiting",
+		AutoBook:   req.AutoBook == nil || *req.AutoBook,
+	}
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := requireGoodStanding(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		// Serialize with bookings and cancellations of the same slot
+		timeSlot, err := lockTimeSlot(ctx, tx, req.TimeSlotID)
+		if err != nil {
+			return fmt.Errorf("time slot not found: %w", err)
+		}
+
+		if !timeSlot.StartTime.After(time.Now()) {
+			return fmt.Errorf("time slot has already started")
+		}
+
+		if timeSlot.BookedCount < timeSlot.Capacity {
+			return fmt.Errorf("time slot has free seats; book it directly")
+		}
+
+		if err := requireStandalone(ctx, tx, timeSlot.ResourceID); err != nil {
+			return err
+		}
+
+		booked, err := tx.NewSelect().
+			Model((*models.Booking)(nil)).
+			Where("time_slot_id = ?", req.TimeSlotID).
+			Where("user_id = ?", userID).
+			Where("status IN ('pending', 'confirmed')").
+			Exists(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to check existing bookings: %w", err)
+		}
+		if booked {
+			return fmt.Errorf("you already have a booking for this time slot")
+		}
+
+		queued, err := tx.NewSelect().
+			Model((*models.WaitlistEntry)(nil)).
+			Where("time_slot_id = ?", req.TimeSlotID).
+			Where("user_id = ?", userID).
+			Where("status IN ('waiting', 'offered')").
+			Exists(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to check waitlist: %w", err)
+		}
+		if queued {
+			return fmt.Errorf("already on the waitlist for this time slot")
+		}
+
+		entry.ResourceID = timeSlot.ResourceID
+
+		_, err = tx.NewInsert().
+			Model(entry).
+			Returning("*").
+			Exec(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to join waitlist: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Leave removes the user from a waitlist. Leaving with an open offer passes
+// the held seat on to the next user in the queue.
+func (s *WaitlistService) Leave(ctx context.Context, entryID, userID uuid.UUID) error {
+	return s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		entry, err := lockWaitlistEntry(ctx, tx, entryID, userID)
+		if err != nil {
+			return err
+		}
+
+		if entry.Status != "waiting" && entry.Status != "offered" {
+			return fmt.Errorf("waitlist entry is no longer active")
+		}
+
+		if err := setWaitlistStatus(ctx, tx, entry.ID, "left"); err != nil {
+			return err
+		}
+
+		if entry.Status != "offered" {
+			return nil
+		}
+
+		if err := releaseSeats(ctx, tx, entry.TimeSlotID, 1); err != nil {
+			return err
+		}
+
+		return s.bookings.promoteWaitlist(ctx, tx, entry.TimeSlotID)
+	})
+}
+
+// Claim turns an open offer into a booking using the seat held for the
+// user. A priced seat then waits for its payment like any other booking.
+func (s *WaitlistService) Claim(ctx context.Context, entryID, userID uuid.UUID) (*models.Booking, error) {
+	var booking *models.Booking
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		entry, err := lockWaitlistEntry(ctx, tx, entryID, userID)
+		if err != nil {
+			return err
+		}
+
+		if entry.Status != "offered" {
+			return fmt.Errorf("no open offer for this waitlist entry")
+		}
+
+		// The sweeper may not have run yet; an expired offer is still expired
+		if entry.OfferExpiresAt != nil && !entry.OfferExpiresAt.After(time.Now()) {
+			return fmt.Errorf("waitlist offer has expired")
+		}
+
+		if err := requireGoodStanding(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		timeSlot, err := lockTimeSlot(ctx, tx, entry.TimeSlotID)
+		if err != nil {
+			return fmt.Errorf("time slot not found: %w", err)
+		}
+
+		if err := requireStandalone(ctx, tx, timeSlot.ResourceID); err != nil {
+			return err
+		}
+
+		// Give back the seat held for the offer and book it like any other,
+		// so the booking rules apply to waitlisted users too
+		if err := releaseSeats(ctx, tx, timeSlot.ID, 1); err != nil {
+			return err
+		}
+
+		if timeSlot, err = lockTimeSlot(ctx, tx, timeSlot.ID); err != nil {
+			return fmt.Errorf("time slot not found: %w", err)
+		}
+
+		booking, err = bookLockedSlot(ctx, tx, userID, timeSlot.ResourceID, timeSlot, "", 1, nil)
+		if err != nil {
+			return err
+		}
+
+		if err := s.bookings.requirePayment(ctx, tx, booking); err != nil {
+			return err
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*models.WaitlistEntry)(nil)).
+			Set("status = ?", "booked").
+			Set("booking_id = ?", booking.ID).
+			Set("updated_at = NOW()").
+			Where("id = ?", entry.ID).
+			Exec(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to update waitlist entry: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.bookings.openPayments(ctx, booking)
+	return booking, nil
+}
+
+// ListForUser returns the user's active and past waitlist entries, soonest
+// time slot first.
+func (s *WaitlistService) ListForUser(ctx context.Context, userID uuid.UUID) ([]models.WaitlistPosition, error) {
+	positions := make([]models.WaitlistPosition, 0)
+
+	err := s.db.NewSelect().
+		TableExpr("waitlist_entries AS w").
+		ColumnExpr("w.*, ts.start_time, ts.end_time").
+		ColumnExpr(`CASE WHEN w.status = 'waiting' THEN (
+			SELECT COUNT(*) FROM waitlist_entries q
+			WHERE q.time_slot_id = w.time_slot_id AND q.status = 'waiting'
+			AND (q.created_at, q.id) <= (w.created_at, w.id)
+		) ELSE 0 END AS position`).
+		Join("JOIN time_slots AS ts ON ts.id = w.time_slot_id").
+		Where("w.user_id = ?", userID).
+		OrderExpr("ts.start_time ASC, w.created_at ASC").
+		Scan(ctx, &positions)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list waitlist entries: %w", err)
+	}
+
+	return positions, nil
+}
+
+// ExpireOffers lapses offers past their deadline and passes each held seat
+// on to the next user in the queue. It returns the number of expired offers.
+func (s *WaitlistService) ExpireOffers(ctx context.Context) (int, error) {
+	expired := 0
+
+	err := s.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var entries []models.WaitlistEntry
+		err := tx.NewSelect().
+			Model(&entries).
+			Where("status = ?", "offered").
+			Where("offer_expires_at <= NOW()").
+			Order("offer_expires_at ASC").
+			For("UPDATE SKIP LOCKED").
+			Scan(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to find expired offers: %w", err)
+		}
+
+		for _, entry := range entries {
+			if err := setWaitlistStatus(ctx, tx, entry.ID, "expired"); err != nil {
+				return err
+			}
+
+			if err := releaseSeats(ctx, tx, entry.TimeSlotID, 1); err != nil {
+				return err
+			}
+
+			if err := s.bookings.promoteWaitlist(ctx, tx, entry.TimeSlotID); err != nil {
+				return err
+			}
+		}
+
+		expired = len(entries)
+		return nil
+	})
+
+	return expired, err
+}
+
+// promoteWaitlist hands free seats of a time slot to the head of its queue,
+// booking auto-book entries outright and holding the seat for the others
+// until their offer expires. Slots that have started, are disabled or
+// blacked out, and slots of resources only booked in bundles, are left
+// alone.
+func (s *BookingService) promoteWaitlist(ctx context.Context, tx bun.Tx, timeSlotID uuid.UUID) error {
+	timeSlot, err := lockTimeSlot(ctx, tx, timeSlotID)
+	if err != nil {
+		return fmt.Errorf("time slot not found: %w", err)
+	}
+
+	if timeSlot.IsDisabled || !timeSlot.StartTime.After(time.Now()) {
+		return nil
+	}
+
+	blackedOut, err := slotBlackedOut(ctx, tx, timeSlotID)
+	if err != nil || blackedOut {
+		return err
+	}
+
+	dependent, err := hasDependencies(ctx, tx, timeSlot.ResourceID)
+	if err != nil || dependent {
+		return err
+	}
+
+	for timeSlot.BookedCount < timeSlot.Capacity {
+		var entry models.WaitlistEntry
+		err := tx.NewSelect().
+			Model(&entry).
+			Where("time_slot_id = ?", timeSlotID).
+			Where("status = ?", "waiting").
+			Order("created_at ASC", "id ASC").
+			Limit(1).
+			For("UPDATE SKIP LOCKED").
+			Scan(ctx)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read waitlist: %w", err)
+		}
+
+		update := tx.NewUpdate().
+			Model((*models.WaitlistEntry)(nil)).
+			Set("updated_at = NOW()").
+			Where("id = ?", entry.ID)
+
+		var booking *models.Booking
+		if entry.AutoBook {
+			// A seat the booking rules or the no-show limit deny the user
+			// is offered instead, so they can see it and the offer lapses
+			// to the next in line
+			var ruleErr *BookingRuleError
+			err = requireGoodStanding(ctx, tx, entry.UserID)
+			if err == nil {
+				booking, err = bookLockedSlot(ctx, tx, entry.UserID, timeSlot.ResourceID, timeSlot, "", 1, nil)
+			}
+			if err != nil && !errors.As(err, &ruleErr) && !errors.Is(err, ErrNoShowLimit) {
+				return err
+			}
+
+			// Its intent is created by the payment sweep, as this runs
+			// inside whichever transaction freed the seat
+			if booking != nil {
+				if err := s.requirePayment(ctx, tx, booking); err != nil {
+					return err
+				}
+			}
+		}
+
+		if booking != nil {
+			update = update.
+				Set("status = ?", "booked").
+				Set("booking_id = ?", booking.ID)
+
+			logger.Info().
+				Str("user_id", entry.UserID.String()).
+				Str("time_slot_id", timeSlotID.String()).
+				Str("booking_id", booking.ID.String()).
+				Msg("Booked waitlisted user into freed seat")
+		} else {
+			expiresAt := time.Now().Add(config.AppConfig.WaitlistOfferTTL)
+
+			update = update.
+				Set("status = ?", "offered").
+				Set("offer_expires_at = ?", expiresAt)
+
+			logger.Info().
+				Str("user_id", entry.UserID.String()).
+				Str("time_slot_id", timeSlotID.String()).
+				Time("expires_at", expiresAt).
+				Msg("Offered freed seat to waitlisted user")
+		}
+
+		if _, err := update.Exec(ctx); err != nil {
+			return fmt.Errorf("failed to update waitlist entry: %w", err)
+		}
+
+		// bookLockedSlot took the seat of a booking itself
+		if booking == nil {
+			if err := reserveSeats(ctx, tx, timeSlotID, 1); err != nil {
+				return err
+			}
+		}
+
+		if timeSlot, err = lockTimeSlot(ctx, tx, timeSlotID); err != nil {
+			return fmt.Errorf("time slot not found: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// lockWaitlistEntry loads one of the user's waitlist entries with a row lock.
+func lockWaitlistEntry(ctx context.Context, tx bun.Tx, entryID, userID uuid.UUID) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := tx.NewSelect().
+		Model(&entry).
+		Where("id = ?", entryID).
+		Where("user_id = ?", userID).
+		For("UPDATE").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("waitlist entry not found: %w", err)
+	}
+
+	return &entry, nil
+}
+
+func setWaitlistStatus(ctx context.Context, tx bun.Tx, entryID uuid.UUID, status string) error {
+	_, err := tx.NewUpdate().
+		Model((*models.WaitlistEntry)(nil)).
+		Set("status = ?", status).
+		Set("updated_at = NOW()").
+		Where("id = ?", entryID).
+		Exec(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to update waitlist entry: %w", err)
+	}
+
+	return nil
+}
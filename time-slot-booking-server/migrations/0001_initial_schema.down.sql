@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS bookings;
+DROP TABLE IF EXISTS time_slots;
+DROP TABLE IF EXISTS resources;
+DROP TABLE IF EXISTS app_users;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,2 @@
+ALTER TABLE time_slots DROP CONSTRAINT IF EXISTS time_slot_capacity;
+ALTER TABLE time_slots DROP COLUMN IF EXISTS booked_count;
@@ -0,0 +1,12 @@
+-- Seat usage is tracked on the slot itself so capacity is enforced by the
+-- database rather than by a count-then-insert in application code.
+ALTER TABLE time_slots ADD COLUMN IF NOT EXISTS booked_count INTEGER NOT NULL DEFAULT 0;
+
+UPDATE time_slots ts SET booked_count = (
+	SELECT COUNT(*) FROM bookings b
+	WHERE b.time_slot_id = ts.id AND b.status IN ('pending', 'confirmed')
+);
+
+ALTER TABLE time_slots DROP CONSTRAINT IF EXISTS time_slot_capacity;
+ALTER TABLE time_slots
+	ADD CONSTRAINT time_slot_capacity CHECK (booked_count >= 0 AND booked_count <= capacity);
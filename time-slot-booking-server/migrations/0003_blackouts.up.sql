@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS blackouts (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	scope VARCHAR NOT NULL,
+	resource_type VARCHAR,
+	resource_id UUID REFERENCES resources(id) ON DELETE CASCADE,
+	starts_at TIMESTAMP NOT NULL,
+	ends_at TIMESTAMP NOT NULL,
+	reason TEXT,
+	created_by UUID REFERENCES app_users(id) ON DELETE SET NULL,
+	created_at TIMESTAMP DEFAULT NOW(),
+	CONSTRAINT valid_blackout_range CHECK (ends_at > starts_at),
+	CONSTRAINT valid_blackout_scope CHECK (
+		(scope = 'global' AND resource_type IS NULL AND resource_id IS NULL) OR
+		(scope = 'resource_type' AND resource_type IS NOT NULL AND resource_id IS NULL) OR
+		(scope = 'resource' AND resource_id IS NOT NULL AND resource_type IS NULL)
+	)
+);
+
+CREATE INDEX IF NOT EXISTS idx_blackouts_range ON blackouts(starts_at, ends_at);
+CREATE INDEX IF NOT EXISTS idx_blackouts_resource ON blackouts(resource_id);
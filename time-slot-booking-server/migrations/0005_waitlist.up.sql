@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	time_slot_id UUID NOT NULL REFERENCES time_slots(id) ON DELETE CASCADE,
+	resource_id UUID NOT NULL REFERENCES resources(id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES app_users(id) ON DELETE CASCADE,
+	status VARCHAR NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'offered', 'booked', 'left', 'expired')),
+	auto_book BOOLEAN NOT NULL DEFAULT true,
+	booking_id UUID REFERENCES bookings(id) ON DELETE SET NULL,
+	offer_expires_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A user holds at most one live place in a slot's queue
+CREATE UNIQUE INDEX IF NOT EXISTS idx_waitlist_active_user
+	ON waitlist_entries(time_slot_id, user_id)
+	WHERE status IN ('waiting', 'offered');
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_queue ON waitlist_entries(time_slot_id, created_at) WHERE status = 'waiting';
+CREATE INDEX IF NOT EXISTS idx_waitlist_offers ON waitlist_entries(offer_expires_at) WHERE status = 'offered';
+CREATE INDEX IF NOT EXISTS idx_waitlist_user ON waitlist_entries(user_id);
@@ -0,0 +1,5 @@
+-- Pending bookings with an expiry are checkout holds; they take a seat until
+-- confirmed, cancelled or swept once expired
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS hold_expires_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_hold_expiry ON bookings(hold_expires_at) WHERE status = 'pending';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS booking_transitions;
+
+DROP TABLE IF EXISTS resource_providers;
+
+ALTER TABLE resources DROP COLUMN IF EXISTS requires_approval;
@@ -0,0 +1,24 @@
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS requires_approval BOOLEAN NOT NULL DEFAULT false;
+
+-- Providers who may approve or reject bookings on a resource
+CREATE TABLE IF NOT EXISTS resource_providers (
+	resource_id UUID NOT NULL REFERENCES resources(id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES app_users(id) ON DELETE CASCADE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (resource_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_resource_providers_user ON resource_providers(user_id);
+
+-- Audit trail of booking status changes
+CREATE TABLE IF NOT EXISTS booking_transitions (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+	from_status VARCHAR,
+	to_status VARCHAR NOT NULL,
+	actor_id UUID REFERENCES app_users(id) ON DELETE SET NULL,
+	reason TEXT,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_transitions_booking ON booking_transitions(booking_id, created_at);
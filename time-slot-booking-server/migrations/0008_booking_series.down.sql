@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_bookings_series;
+
+ALTER TABLE bookings DROP COLUMN IF EXISTS series_id;
+
+DROP TABLE IF EXISTS booking_series;
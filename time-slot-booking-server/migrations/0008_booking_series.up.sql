@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS booking_series (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES app_users(id) ON DELETE CASCADE,
+	resource_id UUID NOT NULL REFERENCES resources(id) ON DELETE CASCADE,
+	rule VARCHAR NOT NULL,
+	notes TEXT,
+	status VARCHAR NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'cancelled')),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_series_user ON booking_series(user_id);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS series_id UUID REFERENCES booking_series(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_series ON bookings(series_id) WHERE series_id IS NOT NULL;
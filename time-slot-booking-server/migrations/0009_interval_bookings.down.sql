@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_bookings_resource_interval;
+
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_interval_no_overlap;
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_interval_valid;
+
+DELETE FROM bookings WHERE time_slot_id IS NULL;
+
+ALTER TABLE bookings DROP COLUMN IF EXISTS end_time;
+ALTER TABLE bookings DROP COLUMN IF EXISTS start_time;
+
+ALTER TABLE resources DROP COLUMN IF EXISTS interval_rules;
+ALTER TABLE resources DROP COLUMN IF EXISTS booking_mode;
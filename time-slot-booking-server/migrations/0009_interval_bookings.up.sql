@@ -0,0 +1,25 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS booking_mode VARCHAR NOT NULL DEFAULT 'slots' CHECK (booking_mode IN ('slots', 'interval'));
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS interval_rules JSONB;
+
+-- Every booking carries its own interval; slot bookings copy their slot's times
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS start_time TIMESTAMPTZ;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS end_time TIMESTAMPTZ;
+
+UPDATE bookings b
+SET start_time = ts.start_time, end_time = ts.end_time
+FROM time_slots ts
+WHERE ts.id = b.time_slot_id AND b.start_time IS NULL;
+
+ALTER TABLE bookings ALTER COLUMN start_time SET NOT NULL;
+ALTER TABLE bookings ALTER COLUMN end_time SET NOT NULL;
+
+ALTER TABLE bookings ADD CONSTRAINT bookings_interval_valid CHECK (end_time > start_time);
+
+-- Interval bookings on the same resource may not overlap while active
+ALTER TABLE bookings ADD CONSTRAINT bookings_interval_no_overlap
+	EXCLUDE USING gist (resource_id WITH =, tstzrange(start_time, end_time, '[)') WITH &&)
+	WHERE (time_slot_id IS NULL AND status IN ('pending', 'confirmed'));
+
+CREATE INDEX IF NOT EXISTS idx_bookings_resource_interval ON bookings USING gist (resource_id, tstzrange(start_time, end_time, '[)'));
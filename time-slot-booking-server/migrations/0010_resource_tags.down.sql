@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_time_slots_open_start;
+DROP INDEX IF EXISTS idx_resources_type;
+DROP INDEX IF EXISTS idx_resources_tags;
+
+ALTER TABLE resources DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,7 @@
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_resources_tags ON resources USING gin (tags);
+CREATE INDEX IF NOT EXISTS idx_resources_type ON resources(type);
+
+-- Cross-resource search scans open slots by start time
+CREATE INDEX IF NOT EXISTS idx_time_slots_open_start ON time_slots(start_time) WHERE is_available;
@@ -0,0 +1,4 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS pool_id;
+
+DROP TABLE IF EXISTS resource_pool_members;
+DROP TABLE IF EXISTS resource_pools;
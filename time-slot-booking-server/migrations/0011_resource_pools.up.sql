@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS resource_pools (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	name VARCHAR NOT NULL,
+	description TEXT,
+	strategy VARCHAR NOT NULL DEFAULT 'least_used' CHECK (strategy IN ('least_used', 'round_robin', 'priority')),
+	-- Round-robin resumes after the resource assigned last
+	last_resource_id UUID REFERENCES resources(id) ON DELETE SET NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS resource_pool_members (
+	pool_id UUID NOT NULL REFERENCES resource_pools(id) ON DELETE CASCADE,
+	resource_id UUID NOT NULL REFERENCES resources(id) ON DELETE CASCADE,
+	priority INTEGER NOT NULL DEFAULT 0,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (pool_id, resource_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_resource_pool_members_resource ON resource_pool_members(resource_id);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS pool_id UUID REFERENCES resource_pools(id) ON DELETE SET NULL;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS resource_dependencies;
+
+DROP INDEX IF EXISTS idx_bookings_bundle;
+
+ALTER TABLE bookings DROP COLUMN IF EXISTS bundle_id;
+
+DROP TABLE IF EXISTS booking_bundles;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS booking_bundles (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES app_users(id) ON DELETE CASCADE,
+	start_time TIMESTAMPTZ NOT NULL,
+	end_time TIMESTAMPTZ NOT NULL,
+	notes TEXT,
+	status VARCHAR NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'cancelled')),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_bundles_user ON booking_bundles(user_id);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS bundle_id UUID REFERENCES booking_bundles(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_bundle ON bookings(bundle_id) WHERE bundle_id IS NOT NULL;
+
+-- A resource that depends on a pool can only be booked together with a member of it
+CREATE TABLE IF NOT EXISTS resource_dependencies (
+	resource_id UUID NOT NULL REFERENCES resources(id) ON DELETE CASCADE,
+	pool_id UUID NOT NULL REFERENCES resource_pools(id) ON DELETE CASCADE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (resource_id, pool_id)
+);
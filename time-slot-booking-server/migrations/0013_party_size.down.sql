@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS booking_attendees;
+
+ALTER TABLE bookings DROP COLUMN IF EXISTS party_size;
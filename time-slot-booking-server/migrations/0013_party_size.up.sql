@@ -0,0 +1,11 @@
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS party_size INTEGER NOT NULL DEFAULT 1 CHECK (party_size >= 1);
+
+CREATE TABLE IF NOT EXISTS booking_attendees (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+	name VARCHAR NOT NULL,
+	email VARCHAR,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_attendees_booking ON booking_attendees(booking_id);
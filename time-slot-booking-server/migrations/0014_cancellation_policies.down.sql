@@ -0,0 +1,5 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS cancellation_policy;
+ALTER TABLE bookings DROP COLUMN IF EXISTS refund_amount;
+ALTER TABLE bookings DROP COLUMN IF EXISTS cancellation_fee;
+
+ALTER TABLE resources DROP COLUMN IF EXISTS cancellation_policy;
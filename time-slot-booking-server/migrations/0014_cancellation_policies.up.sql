@@ -0,0 +1,6 @@
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS cancellation_policy JSONB;
+
+-- What a cancellation cost and the policy it was priced under, kept for disputes
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS cancellation_fee DECIMAL(10,2);
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS refund_amount DECIMAL(10,2);
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS cancellation_policy JSONB;
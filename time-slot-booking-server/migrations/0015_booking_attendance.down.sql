@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_bookings_user_no_show;
+
+ALTER TABLE bookings DROP COLUMN IF EXISTS attendance_marked_at;
+ALTER TABLE bookings DROP COLUMN IF EXISTS attendance;
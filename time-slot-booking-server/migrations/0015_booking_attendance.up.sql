@@ -0,0 +1,11 @@
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS attendance VARCHAR CHECK (attendance IN ('checked_in', 'no_show', 'completed'));
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS attendance_marked_at TIMESTAMPTZ;
+
+-- Bookings that started before attendance was tracked count as used, so the
+-- no-show sweep does not mark them
+UPDATE bookings
+SET attendance = 'completed', attendance_marked_at = NOW()
+WHERE status = 'confirmed' AND attendance IS NULL AND start_time < NOW();
+
+-- Recent no-shows per user drive booking restrictions
+CREATE INDEX IF NOT EXISTS idx_bookings_user_no_show ON bookings(user_id, start_time) WHERE attendance = 'no_show';
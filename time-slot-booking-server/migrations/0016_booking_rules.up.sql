@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS booking_rules (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	scope VARCHAR NOT NULL,
+	resource_type VARCHAR,
+	resource_id UUID REFERENCES resources(id) ON DELETE CASCADE,
+	min_notice_minutes INTEGER CHECK (min_notice_minutes >= 0),
+	max_advance_days INTEGER CHECK (max_advance_days > 0),
+	max_active_bookings INTEGER CHECK (max_active_bookings > 0),
+	max_hours_per_week NUMERIC(6,2) CHECK (max_hours_per_week > 0),
+	one_per_day BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	CONSTRAINT valid_booking_rule_scope CHECK (
+		(scope = 'global' AND resource_type IS NULL AND resource_id IS NULL) OR
+		(scope = 'resource_type' AND resource_type IS NOT NULL AND resource_id IS NULL) OR
+		(scope = 'resource' AND resource_id IS NOT NULL AND resource_type IS NULL)
+	)
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_rules_resource ON booking_rules(resource_id);
+CREATE INDEX IF NOT EXISTS idx_booking_rules_type ON booking_rules(resource_type);
@@ -0,0 +1,5 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS price_breakdown;
+
+DROP TABLE IF EXISTS pricing_rules;
+
+ALTER TABLE app_users DROP COLUMN IF EXISTS is_member;
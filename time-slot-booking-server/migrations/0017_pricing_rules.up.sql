@@ -0,0 +1,29 @@
+ALTER TABLE app_users ADD COLUMN IF NOT EXISTS is_member BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS pricing_rules (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	scope VARCHAR NOT NULL,
+	resource_type VARCHAR,
+	resource_id UUID REFERENCES resources(id) ON DELETE CASCADE,
+	name VARCHAR NOT NULL,
+	kind VARCHAR NOT NULL CHECK (kind IN ('peak', 'off_peak', 'weekend', 'member', 'early_bird', 'last_minute', 'per_seat')),
+	weekdays TEXT[],
+	open VARCHAR,
+	close VARCHAR,
+	hours_before NUMERIC(8,2) CHECK (hours_before > 0),
+	min_party_size INTEGER NOT NULL DEFAULT 0 CHECK (min_party_size >= 0),
+	adjust_percent NUMERIC(6,2) NOT NULL DEFAULT 0 CHECK (adjust_percent >= -100),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	CONSTRAINT valid_pricing_rule_scope CHECK (
+		(scope = 'global' AND resource_type IS NULL AND resource_id IS NULL) OR
+		(scope = 'resource_type' AND resource_type IS NOT NULL AND resource_id IS NULL) OR
+		(scope = 'resource' AND resource_id IS NOT NULL AND resource_type IS NULL)
+	)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pricing_rules_resource ON pricing_rules(resource_id);
+CREATE INDEX IF NOT EXISTS idx_pricing_rules_type ON pricing_rules(resource_type);
+
+-- The itemised price a booking was made at, so later rule changes do not reprice it
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS price_breakdown JSONB;
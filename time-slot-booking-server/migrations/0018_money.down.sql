@@ -0,0 +1,26 @@
+UPDATE bookings
+SET price_breakdown = (
+	SELECT jsonb_agg(line || jsonb_build_object('amount', (line->'amount'->>'amount')::NUMERIC / 100) ORDER BY position)
+	FROM jsonb_array_elements(price_breakdown) WITH ORDINALITY AS lines(line, position)
+)
+WHERE jsonb_typeof(price_breakdown) = 'array' AND jsonb_array_length(price_breakdown) > 0;
+
+UPDATE resources
+SET interval_rules = jsonb_set(interval_rules, '{hourly_rate}', to_jsonb((interval_rules->'hourly_rate'->>'amount')::NUMERIC / 100))
+WHERE jsonb_typeof(interval_rules->'hourly_rate') = 'object';
+
+UPDATE resources
+SET operating_hours = jsonb_set(operating_hours, '{default_price}', to_jsonb((operating_hours->'default_price'->>'amount')::NUMERIC / 100))
+WHERE jsonb_typeof(operating_hours->'default_price') = 'object';
+
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS booking_single_currency;
+
+-- Currencies are dropped; amounts are assumed to have two decimal places
+ALTER TABLE bookings ALTER COLUMN refund_amount TYPE DECIMAL(10,2) USING (refund_amount).amount / 100.0;
+ALTER TABLE bookings ALTER COLUMN cancellation_fee TYPE DECIMAL(10,2) USING (cancellation_fee).amount / 100.0;
+ALTER TABLE bookings ALTER COLUMN total_amount TYPE DECIMAL(10,2) USING (total_amount).amount / 100.0;
+ALTER TABLE time_slots ALTER COLUMN price TYPE DECIMAL(10,2) USING (price).amount / 100.0;
+
+ALTER TABLE resources DROP COLUMN IF EXISTS currency;
+
+DROP TYPE IF EXISTS money_amount;
@@ -0,0 +1,44 @@
+-- Amounts are integer minor units of an ISO 4217 currency, e.g. (2550,USD) is $25.50
+CREATE TYPE money_amount AS (
+	amount BIGINT,
+	currency CHAR(3)
+);
+
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+-- Every resource starts out in USD, so existing decimal amounts are USD cents
+ALTER TABLE time_slots ALTER COLUMN price TYPE money_amount
+	USING CASE WHEN price IS NULL THEN NULL ELSE ROW(ROUND(price * 100)::BIGINT, 'USD')::money_amount END;
+ALTER TABLE bookings ALTER COLUMN total_amount TYPE money_amount
+	USING CASE WHEN total_amount IS NULL THEN NULL ELSE ROW(ROUND(total_amount * 100)::BIGINT, 'USD')::money_amount END;
+ALTER TABLE bookings ALTER COLUMN cancellation_fee TYPE money_amount
+	USING CASE WHEN cancellation_fee IS NULL THEN NULL ELSE ROW(ROUND(cancellation_fee * 100)::BIGINT, 'USD')::money_amount END;
+ALTER TABLE bookings ALTER COLUMN refund_amount TYPE money_amount
+	USING CASE WHEN refund_amount IS NULL THEN NULL ELSE ROW(ROUND(refund_amount * 100)::BIGINT, 'USD')::money_amount END;
+
+-- A booking is charged and refunded in a single currency
+ALTER TABLE bookings ADD CONSTRAINT booking_single_currency CHECK (
+	(cancellation_fee IS NULL OR (cancellation_fee).currency = (total_amount).currency) AND
+	(refund_amount IS NULL OR (refund_amount).currency = (total_amount).currency)
+);
+
+UPDATE resources
+SET operating_hours = jsonb_set(operating_hours, '{default_price}', jsonb_build_object(
+	'amount', ROUND((operating_hours->>'default_price')::NUMERIC * 100)::BIGINT,
+	'currency', currency))
+WHERE jsonb_typeof(operating_hours->'default_price') = 'number';
+
+UPDATE resources
+SET interval_rules = jsonb_set(interval_rules, '{hourly_rate}', jsonb_build_object(
+	'amount', ROUND((interval_rules->>'hourly_rate')::NUMERIC * 100)::BIGINT,
+	'currency', currency))
+WHERE jsonb_typeof(interval_rules->'hourly_rate') = 'number';
+
+UPDATE bookings
+SET price_breakdown = (
+	SELECT jsonb_agg(line || jsonb_build_object('amount', jsonb_build_object(
+		'amount', ROUND((line->>'amount')::NUMERIC * 100)::BIGINT,
+		'currency', 'USD')) ORDER BY position)
+	FROM jsonb_array_elements(price_breakdown) WITH ORDINALITY AS lines(line, position)
+)
+WHERE jsonb_typeof(price_breakdown) = 'array' AND jsonb_array_length(price_breakdown) > 0;
@@ -0,0 +1,4 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS payment_status;
+
+DROP TABLE IF EXISTS payment_events;
+DROP TABLE IF EXISTS payments;
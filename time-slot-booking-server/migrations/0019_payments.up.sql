@@ -0,0 +1,31 @@
+-- A priced booking is paid through one payment; bookings made before
+-- payments were enabled have none
+CREATE TABLE IF NOT EXISTS payments (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+	provider VARCHAR NOT NULL,
+	provider_intent_id VARCHAR NOT NULL,
+	amount money_amount NOT NULL,
+	status VARCHAR NOT NULL DEFAULT 'requires_payment' CHECK (status IN ('requires_payment', 'succeeded', 'failed', 'expired', 'cancelled')),
+	client_secret VARCHAR,
+	expires_at TIMESTAMPTZ NOT NULL,
+	paid_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	UNIQUE (provider, provider_intent_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_booking ON payments(booking_id);
+
+-- Webhook events already handled; providers redeliver events, and a
+-- redelivered event must not be applied twice
+CREATE TABLE IF NOT EXISTS payment_events (
+	provider VARCHAR NOT NULL,
+	id VARCHAR NOT NULL,
+	type VARCHAR NOT NULL,
+	provider_intent_id VARCHAR NOT NULL,
+	received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (provider, id)
+);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS payment_status VARCHAR CHECK (payment_status IN ('awaiting_payment', 'paid'));
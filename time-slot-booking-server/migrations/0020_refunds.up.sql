@@ -0,0 +1,16 @@
+-- Money returned on a booking's payment, automatically on cancellation or
+-- by an admin
+CREATE TABLE IF NOT EXISTS refunds (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+	payment_id UUID NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+	provider_refund_id VARCHAR NOT NULL,
+	amount money_amount NOT NULL CHECK ((amount).amount > 0),
+	kind VARCHAR NOT NULL CHECK (kind IN ('cancellation', 'manual', 'goodwill')),
+	reason TEXT,
+	actor_id UUID REFERENCES app_users(id) ON DELETE SET NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_booking ON refunds(booking_id);
+CREATE INDEX IF NOT EXISTS idx_refunds_payment ON refunds(payment_id);
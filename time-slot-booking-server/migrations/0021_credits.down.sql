@@ -0,0 +1,6 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS credits_used;
+ALTER TABLE bookings DROP COLUMN IF EXISTS credit_pack_id;
+
+DROP TABLE IF EXISTS credit_transactions;
+DROP TABLE IF EXISTS credit_packs;
+DROP TABLE IF EXISTS credit_products;
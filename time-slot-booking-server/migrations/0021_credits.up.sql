@@ -0,0 +1,50 @@
+-- Prepaid packs users buy, such as a 10-session card, good for resources of
+-- the listed types (any type when empty)
+CREATE TABLE IF NOT EXISTS credit_products (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	name VARCHAR NOT NULL,
+	description TEXT,
+	resource_types TEXT[] NOT NULL DEFAULT '{}',
+	credits INTEGER NOT NULL CHECK (credits > 0),
+	price money_amount,
+	validity_days INTEGER CHECK (validity_days > 0),
+	active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A product bought by a user; terms are copied so later product changes do
+-- not affect packs already sold
+CREATE TABLE IF NOT EXISTS credit_packs (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES app_users(id) ON DELETE CASCADE,
+	product_id UUID REFERENCES credit_products(id) ON DELETE SET NULL,
+	name VARCHAR NOT NULL,
+	resource_types TEXT[] NOT NULL DEFAULT '{}',
+	credits INTEGER NOT NULL CHECK (credits > 0),
+	remaining INTEGER NOT NULL CHECK (remaining >= 0 AND remaining <= credits),
+	expires_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_credit_packs_user ON credit_packs(user_id, expires_at);
+CREATE INDEX IF NOT EXISTS idx_credit_packs_expiring ON credit_packs(expires_at) WHERE remaining > 0;
+
+-- Every change to a pack's remaining credits
+CREATE TABLE IF NOT EXISTS credit_transactions (
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES app_users(id) ON DELETE CASCADE,
+	pack_id UUID NOT NULL REFERENCES credit_packs(id) ON DELETE CASCADE,
+	booking_id UUID REFERENCES bookings(id) ON DELETE SET NULL,
+	kind VARCHAR NOT NULL CHECK (kind IN ('purchase', 'debit', 'refund', 'forfeit', 'expiry')),
+	credits INTEGER NOT NULL,
+	note TEXT,
+	actor_id UUID REFERENCES app_users(id) ON DELETE SET NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_credit_transactions_user ON credit_transactions(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_credit_transactions_booking ON credit_transactions(booking_id);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS credit_pack_id UUID REFERENCES credit_packs(id) ON DELETE SET NULL;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS credits_used INTEGER NOT NULL DEFAULT 0 CHECK (credits_used >= 0);
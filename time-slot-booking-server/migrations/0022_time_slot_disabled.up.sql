@@ -0,0 +1,5 @@
+-- An admin closing a slot is recorded apart from the slot filling up, so
+-- seats given back to a closed slot do not reopen it
+ALTER TABLE time_slots ADD COLUMN IF NOT EXISTS is_disabled BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE time_slots SET is_disabled = true WHERE NOT is_available AND booked_count < capacity;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_payments_without_intent;
+
+DELETE FROM payments WHERE provider_intent_id IS NULL;
+ALTER TABLE payments ALTER COLUMN provider_intent_id SET NOT NULL;
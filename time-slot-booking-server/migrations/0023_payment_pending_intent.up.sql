@@ -0,0 +1,6 @@
+-- A payment is recorded with its booking and the provider's intent is
+-- created once the booking has committed, so a rolled back booking leaves
+-- no intent behind; payments whose intent is still missing are retried
+ALTER TABLE payments ALTER COLUMN provider_intent_id DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_payments_without_intent ON payments(created_at) WHERE provider_intent_id IS NULL;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_refunds_pending;
+DROP INDEX IF EXISTS idx_refunds_idempotency_key;
+
+DELETE FROM refunds WHERE status = 'pending';
+ALTER TABLE refunds DROP COLUMN IF EXISTS idempotency_key;
+ALTER TABLE refunds DROP COLUMN IF EXISTS status;
+ALTER TABLE refunds ALTER COLUMN provider_refund_id SET NOT NULL;
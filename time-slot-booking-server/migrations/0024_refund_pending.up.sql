@@ -0,0 +1,13 @@
+-- A refund is recorded as pending with the cancellation that causes it and
+-- sent to the provider once that has committed, so a rolled back
+-- cancellation pays nothing out; pending refunds are retried under their
+-- idempotency key
+ALTER TABLE refunds ALTER COLUMN provider_refund_id DROP NOT NULL;
+ALTER TABLE refunds ADD COLUMN IF NOT EXISTS status VARCHAR NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded'));
+ALTER TABLE refunds ADD COLUMN IF NOT EXISTS idempotency_key VARCHAR;
+
+UPDATE refunds SET status = 'succeeded', idempotency_key = 'refund-' || id WHERE idempotency_key IS NULL;
+ALTER TABLE refunds ALTER COLUMN idempotency_key SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refunds_idempotency_key ON refunds(idempotency_key);
+CREATE INDEX IF NOT EXISTS idx_refunds_pending ON refunds(created_at) WHERE status = 'pending';
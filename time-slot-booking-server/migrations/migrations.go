@@ -0,0 +1,11 @@
+// Package migrations embeds the versioned SQL schema migrations.
+//
+// Each migration is a pair of files named NNNN_description.up.sql and
+// NNNN_description.down.sql. Versions are applied in ascending order and
+// recorded in the schema_migrations table.
+package migrations
+
+import "embed"
+
+//go:embed *.up.sql *.down.sql
+var FS embed.FS
@@ -1,5 +1,6 @@
 import { useState, useEffect } from 'react';
 import type { Resource, TimeSlot, CreateTimeSlotRequest } from '../types';
+import { formatMoney, toMinorUnits } from '@/lib/utils';
 import { createTimeSlot, createTimeSlotsBulk, deleteTimeSlot, getAvailability, type BulkTimeSlotRequest } from '../services/api';
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from './ui/card';
 import { Button } from './ui/button';
@@ -149,7 +150,7 @@ const AdminPanel: React.FC<AdminPanelProps> = ({ resources, onTimeSlotCreated })
         start_time: datetimeLocalToISO(startTime),
         end_time: datetimeLocalToISO(endTime),
         capacity: capacity,
-        price: price ? parseFloat(price) : undefined,
+        price: price ? { amount: toMinorUnits(price, selectedResource?.currency ?? 'USD') } : undefined,
       };
 
       await createTimeSlot(selectedResourceId, timeSlotData);
@@ -207,7 +208,7 @@ const AdminPanel: React.FC<AdminPanelProps> = ({ resources, onTimeSlotCreated })
         increment: bulkIncrement,
         count: bulkCount,
         capacity: bulkCapacity,
-        price: bulkPrice ? parseFloat(bulkPrice) : undefined,
+        price: bulkPrice ? { amount: toMinorUnits(bulkPrice, selectedResource?.currency ?? 'USD') } : undefined,
       };
 
       await createTimeSlotsBulk(selectedResourceId, bulkData);
@@ -714,7 +715,7 @@ const AdminPanel: React.FC<AdminPanelProps> = ({ resources, onTimeSlotCreated })
                                   {slot.price && (
                                     <div className="flex items-center space-x-1">
                                       <DollarSign className="h-3 w-3" />
-                                      <span>${slot.price.toFixed(2)}</span>
+                                      <span>{formatMoney(slot.price)}</span>
                                     </div>
                                   )}
                                 </div>
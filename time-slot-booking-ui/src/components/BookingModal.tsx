@@ -1,5 +1,6 @@
 import { useState } from 'react';
 import type { Resource, TimeSlot, CreateBookingRequest } from '../types';
+import { formatMoney } from '@/lib/utils';
 import { createBooking } from '../services/api';
 import { Dialog, DialogContent, DialogDescription, DialogHeader, DialogTitle } from './ui/dialog';
 import { Button } from './ui/button';
@@ -122,7 +123,7 @@ const BookingModal: React.FC<BookingModalProps> = ({
                 {timeSlot.price && (
                   <div className="flex items-center space-x-1">
                     <DollarSign className="h-3 w-3 text-green-600" />
-                    <span className="font-semibold">${timeSlot.price}</span>
+                    <span className="font-semibold">{formatMoney(timeSlot.price)}</span>
                   </div>
                 )}
               </div>
@@ -1,4 +1,5 @@
 import type { Booking } from '../types';
+import { formatMoney } from '@/lib/utils';
 import { cancelBooking } from '../services/api';
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from './ui/card';
 import { Button } from './ui/button';
@@ -133,7 +134,7 @@ const MyBookings: React.FC<MyBookingsProps> = ({ bookings, onBookingCancelled })
                             {booking.time_slot.price && (
                               <div className="flex items-center space-x-1 ml-auto">
                                 <DollarSign className="h-3 w-3" />
-                                <span className="font-semibold">${booking.time_slot.price}</span>
+                                <span className="font-semibold">{formatMoney(booking.time_slot.price)}</span>
                               </div>
                             )}
                           </div>
@@ -149,7 +150,7 @@ const MyBookings: React.FC<MyBookingsProps> = ({ bookings, onBookingCancelled })
                         </Badge>
                         {booking.total_amount && (
                           <span className="text-sm text-gray-600">
-                            Total: ${booking.total_amount}
+                            Total: {formatMoney(booking.total_amount)}
                           </span>
                         )}
                       </div>
@@ -4,3 +4,18 @@ import { twMerge } from "tailwind-merge"
 export function cn(...inputs: ClassValue[]) {
   return twMerge(clsx(inputs))
 }
+
+function minorDigits(currency: string) {
+  return new Intl.NumberFormat(undefined, { style: "currency", currency }).resolvedOptions().maximumFractionDigits ?? 2
+}
+
+// formatMoney renders an amount in minor units, e.g. { amount: 2550, currency: "USD" } as $25.50
+export function formatMoney(money: { amount: number; currency: string }) {
+  return new Intl.NumberFormat(undefined, { style: "currency", currency: money.currency })
+    .format(money.amount / 10 ** minorDigits(money.currency))
+}
+
+// toMinorUnits converts a price typed in major units, e.g. "25.50", to minor units of currency
+export function toMinorUnits(value: string, currency: string) {
+  return Math.round(parseFloat(value) * 10 ** minorDigits(currency))
+}
@@ -108,7 +108,7 @@ export interface BulkTimeSlotRequest {
   increment: number; // in minutes
   count: number;
   capacity: number;
-  price?: number;
+  price?: { amount: number; currency?: string };
 }
 
 export async function createTimeSlotsBulk(resourceId: string, data: BulkTimeSlotRequest): Promise<TimeSlot[]> {
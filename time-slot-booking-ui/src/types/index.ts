@@ -1,3 +1,9 @@
+// Money is an amount in minor units of an ISO 4217 currency, e.g. 2550 USD is $25.50
+export interface Money {
+  amount: number;
+  currency: string;
+}
+
 export interface Resource {
   id: string;
   name: string;
@@ -5,6 +11,7 @@ export interface Resource {
   description: string;
   location: string;
   capacity: number;
+  currency: string;
   operating_hours: {
     open: string;
     close: string;
@@ -20,7 +27,7 @@ export interface TimeSlot {
   end_time: string;
   capacity: number;
   is_available: boolean;
-  price?: number;
+  price?: Money;
   created_at: string;
 }
 
@@ -31,7 +38,7 @@ export interface Booking {
   time_slot_id: string;
   status: 'pending' | 'confirmed' | 'cancelled';
   notes?: string;
-  total_amount?: number;
+  total_amount?: Money;
   created_at: string;
   updated_at: string;
   // Populated fields
@@ -49,7 +56,7 @@ export interface CreateTimeSlotRequest {
   start_time: string;
   end_time: string;
   capacity: number;
-  price?: number;
+  price?: { amount: number; currency?: string };
 }
 
 export interface AvailabilityRequest {